@@ -0,0 +1,261 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Person attributes
+// People carry time-based attributes such as their political party or electoral district. A value applies
+// from its start time until the next value of the same attribute starts (or until its own end time).
+// Attributes are written from extra columns of people ADD files or from ATTRIBUTE files. The names of the
+// additional attributes are kept in the metadata of the person, as attributes can only be read by name.
+
+// PersonAttributes lists the attributes that can be given as columns of a people ADD file.
+// Other attributes can be given with an "attr_" prefix, e.g. "attr_portfolio_note".
+var PersonAttributes = []string{"party", "electorate", "parliamentary_status", "honorific"}
+
+// attributeColumnPrefix marks additional attribute columns in people ADD files
+const attributeColumnPrefix = "attr_"
+
+// attributesFromTransaction collects the non-empty attribute columns of a transaction as time-based
+// attribute values starting at the given date
+func attributesFromTransaction(transaction map[string]interface{}, dateISO string) []models.AttributeEntry {
+	values := make(map[string]string)
+	for _, name := range PersonAttributes {
+		if value, ok := transaction[name].(string); ok && strings.TrimSpace(value) != "" {
+			values[name] = strings.TrimSpace(value)
+		}
+	}
+	for column, raw := range transaction {
+		value, ok := raw.(string)
+		if !ok || !strings.HasPrefix(column, attributeColumnPrefix) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		values[strings.TrimPrefix(column, attributeColumnPrefix)] = strings.TrimSpace(value)
+	}
+
+	// Sort by name so the entity update is deterministic
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var attributes []models.AttributeEntry
+	for _, name := range names {
+		attributes = append(attributes, models.AttributeEntry{
+			Key: name,
+			Value: models.AttributeValueCollection{
+				Values: []models.TimeBasedValue{
+					{
+						StartTime: dateISO,
+						Value:     values[name],
+					},
+				},
+			},
+		})
+	}
+	return attributes
+}
+
+// attributeNameMetadata records the names of the attributes that are not known person attributes in the
+// metadata of a person, keyed "attr_<name>". Attributes can only be read by name, so the graph export finds
+// the additional attributes of a person through these entries.
+func attributeNameMetadata(attributes []models.AttributeEntry) []models.MetadataEntry {
+	metadata := []models.MetadataEntry{}
+	for _, attribute := range attributes {
+		if isPersonAttribute(attribute.Key) {
+			continue
+		}
+		metadata = append(metadata, models.MetadataEntry{
+			Key:   attributeColumnPrefix + attribute.Key,
+			Value: attribute.Key,
+		})
+	}
+	return metadata
+}
+
+// isPersonAttribute reports whether an attribute is one of the known person attributes
+func isPersonAttribute(name string) bool {
+	for _, known := range PersonAttributes {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// recordedAttributeNames returns the attributes of a person: the known person attributes followed by the
+// additional ones recorded in its metadata, sorted by name
+func recordedAttributeNames(metadata map[string]interface{}) []string {
+	var additional []string
+	for key := range metadata {
+		if name := strings.TrimPrefix(key, attributeColumnPrefix); name != key && name != "" && !isPersonAttribute(name) {
+			additional = append(additional, name)
+		}
+	}
+	sort.Strings(additional)
+	return append(append([]string{}, PersonAttributes...), additional...)
+}
+
+// setEntityAttributes adds time-based attribute values to an existing person
+func (c *Client) setEntityAttributes(entityID string, attributes []models.AttributeEntry) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	_, err := c.UpdateEntity(entityID, &models.Entity{
+		ID:         entityID,
+		Metadata:   attributeNameMetadata(attributes),
+		Attributes: attributes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set attributes of %s: %w", entityID, err)
+	}
+	return nil
+}
+
+// SetPersonAttribute processes an ATTRIBUTE transaction, setting one attribute of a person from the given date.
+// Columns: transaction_id, child, attribute, value, date and optionally end_date and person_id.
+func (c *Client) SetPersonAttribute(transaction map[string]interface{}) error {
+	// Extract details from the transaction
+	child := transaction["child"].(string)
+	attribute := strings.TrimSpace(transaction["attribute"].(string))
+	value := strings.TrimSpace(transaction["value"].(string))
+	dateStr := transaction["date"].(string)
+
+	if attribute == "" {
+		return fmt.Errorf("attribute name is required")
+	}
+	if value == "" {
+		return fmt.Errorf("value is required for attribute '%s'", attribute)
+	}
+
+	// Parse the dates
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	endDateISO, err := parseOptionalDate(transaction, "end_date")
+	if err != nil {
+		return err
+	}
+	if endDateISO != "" && endDateISO <= dateISO {
+		return fmt.Errorf("end date %s must be after date %s", endDateISO, dateISO)
+	}
+
+	personID, err := c.getPersonID(child, transaction)
+	if err != nil {
+		return err
+	}
+
+	err = c.setEntityAttributes(personID, []models.AttributeEntry{
+		{
+			Key: attribute,
+			Value: models.AttributeValueCollection{
+				Values: []models.TimeBasedValue{
+					{
+						StartTime: dateISO,
+						EndTime:   endDateISO,
+						Value:     value,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.recordSource(transaction, personID, dateISO)
+}
+
+// GetAttributeAsOf returns the value of an entity attribute on the given date (RFC3339).
+// Returns an empty string when the attribute has no value on that date.
+func (c *Client) GetAttributeAsOf(entityID, attribute, dateISO string) (string, error) {
+	result, err := c.GetEntityAttribute(entityID, attribute, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := valueAsOf(parseTimeBasedValues(result), dateISO)
+	if !ok {
+		return "", nil
+	}
+	return fmt.Sprintf("%v", value.Value), nil
+}
+
+// GetPersonAttributesAsOf returns the known person attributes of an entity that have a value on the given date
+func (c *Client) GetPersonAttributesAsOf(entityID, dateISO string) (map[string]string, error) {
+	attributes := make(map[string]string)
+	for _, name := range PersonAttributes {
+		value, err := c.GetAttributeAsOf(entityID, name, dateISO)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attribute %s: %w", name, err)
+		}
+		if value != "" {
+			attributes[name] = value
+		}
+	}
+	return attributes, nil
+}
+
+// valueAsOf picks the value in effect on the given date: the latest value starting on or before the date,
+// unless it has already ended
+func valueAsOf(values []models.TimeBasedValue, dateISO string) (models.TimeBasedValue, bool) {
+	var current *models.TimeBasedValue
+	for i, value := range values {
+		if value.StartTime > dateISO {
+			continue
+		}
+		if current == nil || value.StartTime > current.StartTime {
+			current = &values[i]
+		}
+	}
+	if current == nil || (current.EndTime != "" && current.EndTime <= dateISO) {
+		return models.TimeBasedValue{}, false
+	}
+	return *current, true
+}
+
+// parseTimeBasedValues reads the time-based values from an attribute response, which is either a list of
+// values or an object holding them under "values"
+func parseTimeBasedValues(result interface{}) []models.TimeBasedValue {
+	if object, ok := result.(map[string]interface{}); ok {
+		if nested, exists := object["values"]; exists {
+			return parseTimeBasedValues(nested)
+		}
+		if _, exists := object["value"]; exists {
+			return parseTimeBasedValues([]interface{}{object})
+		}
+		return nil
+	}
+
+	list, ok := result.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var values []models.TimeBasedValue
+	for _, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		startTime, _ := object["startTime"].(string)
+		endTime, _ := object["endTime"].(string)
+		values = append(values, models.TimeBasedValue{
+			StartTime: startTime,
+			EndTime:   endTime,
+			Value:     object["value"],
+		})
+	}
+	return values
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,21 +14,33 @@ import (
 	"orgchart_nexoan/models"
 )
 
+// ErrNotFound is wrapped by the errors of lookups that found no matching entity, test for it with errors.Is
+var ErrNotFound = errors.New("not found")
+
 // Client represents the API client
 type Client struct {
 	updateURL  string
 	queryURL   string
 	httpClient *http.Client
+	people     *PersonRegistry   // optional registry used to resolve person names
+	presidents *PresidentAliases // aliases resolved to president names when loading transactions
+
+	sourceDocuments map[string]string // gazette number -> Document entity ID
+	knownPeople     map[string]bool   // names of the people in the database, loaded once for name suggestions
 }
 
 // NewClient creates a new API client
 func NewClient(updateURL, queryURL string) *Client {
+	// The built-in president aliases are always valid
+	presidents, _ := NewPresidentAliases(nil)
+
 	return &Client{
 		updateURL: updateURL,
 		queryURL:  queryURL,
 		httpClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		presidents: presidents,
 	}
 }
 
@@ -277,7 +290,15 @@ func (c *Client) GetRelatedEntities(entityID string, query *models.Relationship)
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return relations, nil
+	// Relationships replaced by a reinstatement were never in effect, so no reader should see them
+	var current []models.Relationship
+	for _, rel := range relations {
+		if !isReplacedRelationship(rel) {
+			current = append(current, rel)
+		}
+	}
+
+	return current, nil
 }
 
 // GetAllRelatedEntities gets all related entity IDs without filters
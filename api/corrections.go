@@ -0,0 +1,352 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Relationship corrections
+// Gazettes sometimes terminate relationships that were already terminated or refer to entities that only
+// appear later, and mistakes made while loading them have to be undone. REINSTATE reopens a relationship
+// that was ended and AMEND changes the start or end time of an existing relationship. Both are applied
+// through UpdateEntity on the entity owning the relationship and leave an audit entry in its metadata.
+
+// ReinstateRelationship reopens a relationship between parent and child that was ended.
+// The relationship is identified by its current end time ("end_date", defaults to the most recently ended
+// one). Its end time is moved to "new_end" when given. Otherwise the relationship is replaced by an open one
+// with the same start, see reopenRelationship.
+func (c *Client) ReinstateRelationship(transaction map[string]interface{}) error {
+	// Extract details from the transaction
+	parent := transaction["parent"].(string)
+	child := transaction["child"].(string)
+	dateStr := transaction["date"].(string)
+	parentType := transaction["parent_type"].(string)
+	childType := transaction["child_type"].(string)
+	relType := transaction["rel_type"].(string)
+	transactionID := transaction["transaction_id"].(string)
+	presidentName, _ := transaction["president"].(string)
+
+	reason, _ := transaction["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("reason is required when reinstating a relationship")
+	}
+
+	// Parse the dates
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	endDateISO, err := parseOptionalDate(transaction, "end_date")
+	if err != nil {
+		return err
+	}
+	newEndISO, err := parseOptionalDate(transaction, "new_end")
+	if err != nil {
+		return err
+	}
+
+	parentID, childID, relations, err := c.getRelationshipsBetween(parent, parentType, child, childType, relType, presidentName, dateISO)
+	if err != nil {
+		return err
+	}
+
+	// A relationship can only be reopened if there is no active one of the same type
+	if len(activeRelationships(relations)) > 0 {
+		return fmt.Errorf("an active %s relationship already exists between %s and %s", relType, parentID, childID)
+	}
+
+	// Find the ended relationship to reopen
+	var endedRel *models.Relationship
+	for i, rel := range relations {
+		if rel.EndTime == "" {
+			continue
+		}
+		if endDateISO != "" {
+			if rel.EndTime == endDateISO {
+				endedRel = &relations[i]
+				break
+			}
+			continue
+		}
+		if endedRel == nil || rel.EndTime > endedRel.EndTime {
+			endedRel = &relations[i]
+		}
+	}
+	if endedRel == nil {
+		return fmt.Errorf("no ended %s relationship found between %s and %s", relType, parentID, childID)
+	}
+
+	if newEndISO != "" && newEndISO <= endedRel.StartTime {
+		return fmt.Errorf("new end time %s must be after the relationship start time %s", newEndISO, endedRel.StartTime)
+	}
+
+	// The Update API ignores empty fields, so an end time can be moved in place but not cleared
+	var reopenedID string
+	if newEndISO != "" {
+		err = c.updateRelationshipTimes(parentID, endedRel.ID, endedRel.StartTime, newEndISO)
+	} else {
+		reopenedID, err = c.reopenRelationship(parentID, childID, relType, *endedRel)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reinstate relationship: %w", err)
+	}
+
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
+	return c.recordAudit(parentID, models.AuditEntry{
+		Action:         "REINSTATE",
+		TransactionID:  transactionID,
+		RelationshipID: endedRel.ID,
+		ReplacedByID:   reopenedID,
+		Date:           dateISO,
+		Reason:         reason,
+		OldStartTime:   endedRel.StartTime,
+		OldEndTime:     endedRel.EndTime,
+		NewStartTime:   endedRel.StartTime,
+		NewEndTime:     newEndISO,
+	})
+}
+
+// AmendRelationship changes the start and/or end time of an existing relationship between parent and child.
+// The relationship is identified by its current start time ("start_date"), which may be omitted when only
+// one relationship of the type exists. "new_start" and "new_end" give the corrected times.
+func (c *Client) AmendRelationship(transaction map[string]interface{}) error {
+	// Extract details from the transaction
+	parent := transaction["parent"].(string)
+	child := transaction["child"].(string)
+	dateStr := transaction["date"].(string)
+	parentType := transaction["parent_type"].(string)
+	childType := transaction["child_type"].(string)
+	relType := transaction["rel_type"].(string)
+	transactionID := transaction["transaction_id"].(string)
+	presidentName, _ := transaction["president"].(string)
+
+	reason, _ := transaction["reason"].(string)
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("reason is required when amending a relationship")
+	}
+
+	// Parse the dates
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	startDateISO, err := parseOptionalDate(transaction, "start_date")
+	if err != nil {
+		return err
+	}
+	newStartISO, err := parseOptionalDate(transaction, "new_start")
+	if err != nil {
+		return err
+	}
+	newEndISO, err := parseOptionalDate(transaction, "new_end")
+	if err != nil {
+		return err
+	}
+	if newStartISO == "" && newEndISO == "" {
+		return fmt.Errorf("at least one of new_start or new_end is required when amending a relationship")
+	}
+
+	parentID, childID, relations, err := c.getRelationshipsBetween(parent, parentType, child, childType, relType, presidentName, dateISO)
+	if err != nil {
+		return err
+	}
+
+	// Find the relationship to amend
+	var amendedRel *models.Relationship
+	if startDateISO != "" {
+		for i, rel := range relations {
+			if rel.StartTime == startDateISO {
+				amendedRel = &relations[i]
+				break
+			}
+		}
+	} else if len(relations) == 1 {
+		amendedRel = &relations[0]
+	} else if len(relations) > 1 {
+		return fmt.Errorf("multiple %s relationships found between %s and %s, start_date is required", relType, parentID, childID)
+	}
+	if amendedRel == nil {
+		return fmt.Errorf("no %s relationship found between %s and %s", relType, parentID, childID)
+	}
+
+	// Keep the current values for the times that are not amended
+	newStart := amendedRel.StartTime
+	if newStartISO != "" {
+		newStart = newStartISO
+	}
+	newEnd := amendedRel.EndTime
+	if newEndISO != "" {
+		newEnd = newEndISO
+	}
+	if newEnd != "" && newEnd <= newStart {
+		return fmt.Errorf("end time %s must be after start time %s", newEnd, newStart)
+	}
+
+	err = c.updateRelationshipTimes(parentID, amendedRel.ID, newStart, newEnd)
+	if err != nil {
+		return fmt.Errorf("failed to amend relationship: %w", err)
+	}
+
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
+	return c.recordAudit(parentID, models.AuditEntry{
+		Action:         "AMEND",
+		TransactionID:  transactionID,
+		RelationshipID: amendedRel.ID,
+		Date:           dateISO,
+		Reason:         reason,
+		OldStartTime:   amendedRel.StartTime,
+		OldEndTime:     amendedRel.EndTime,
+		NewStartTime:   newStart,
+		NewEndTime:     newEnd,
+	})
+}
+
+// getRelationshipsBetween resolves the parent and child entities and returns all relationships of the
+// given type from the parent to the child, active or not
+func (c *Client) getRelationshipsBetween(parent, parentType, child, childType, relType, presidentName, dateISO string) (string, string, []models.Relationship, error) {
+	parentID, err := c.resolveEntityID(parent, parentType, presidentName, dateISO)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get parent entity: %w", err)
+	}
+
+	// Ministers are resolved under their parent president when the parent is a president
+	childPresident := presidentName
+	if childType == "minister" && (parentType == "president" || parentType == "citizen") {
+		childPresident = parent
+	}
+	childID, err := c.resolveEntityID(child, childType, childPresident, dateISO)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get child entity: %w", err)
+	}
+
+	relations, err := c.GetRelatedEntities(parentID, &models.Relationship{
+		RelatedEntityID: childID,
+		Name:            relType,
+	})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to get relationship: %w", err)
+	}
+
+	return parentID, childID, relations, nil
+}
+
+// updateRelationshipTimes sets both the start and end time of an existing relationship.
+// An empty end time is ignored by the Update API and leaves the current end time.
+func (c *Client) updateRelationshipTimes(ownerID, relationshipID, startISO, endISO string) error {
+	_, err := c.UpdateEntity(ownerID, &models.Entity{
+		ID: ownerID,
+		Relationships: []models.RelationshipEntry{
+			{
+				Key: relationshipID,
+				Value: models.Relationship{
+					StartTime: startISO,
+					EndTime:   endISO,
+					ID:        relationshipID,
+				},
+			},
+		},
+	})
+	return err
+}
+
+// reopenRelationship replaces an ended relationship with an open one starting at the same time, as its end
+// time cannot be cleared through the Update API. The ended relationship is cut to end at its own start, so it
+// is never in effect, and GetRelatedEntities leaves it out. Returns the ID of the new relationship.
+func (c *Client) reopenRelationship(parentID, childID, relType string, ended models.Relationship) (string, error) {
+	err := c.terminateRelationship(parentID, ended.ID, ended.StartTime)
+	if err != nil {
+		return "", err
+	}
+
+	relationshipID := newRelationshipID(parentID, childID)
+	_, err = c.UpdateEntity(parentID, &models.Entity{
+		ID: parentID,
+		Relationships: []models.RelationshipEntry{
+			{
+				Key: relationshipID,
+				Value: models.Relationship{
+					RelatedEntityID: childID,
+					StartTime:       ended.StartTime,
+					EndTime:         "",
+					ID:              relationshipID,
+					Name:            relType,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s relationship from %s to %s: %w", relType, parentID, childID, err)
+	}
+	return relationshipID, nil
+}
+
+// isReplacedRelationship reports whether a relationship was replaced by a reinstatement, ending at its own start
+func isReplacedRelationship(rel models.Relationship) bool {
+	return rel.EndTime != "" && rel.EndTime == rel.StartTime
+}
+
+// recordAudit stores an audit entry in the metadata of the entity owning the corrected relationship.
+// The entry is keyed by transaction ID so re-running the same correction overwrites its own entry.
+func (c *Client) recordAudit(entityID string, entry models.AuditEntry) error {
+	auditJSON, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	_, err = c.UpdateEntity(entityID, &models.Entity{
+		ID: entityID,
+		Metadata: []models.MetadataEntry{
+			{
+				Key:   fmt.Sprintf("audit_%s", entry.TransactionID),
+				Value: string(auditJSON),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	fmt.Printf("Audit: %s %s on %s (%s -> %s) reason: %s\n", entry.Action, entry.RelationshipID, entityID,
+		formatTimeRange(entry.OldStartTime, entry.OldEndTime), formatTimeRange(entry.NewStartTime, entry.NewEndTime), entry.Reason)
+
+	return nil
+}
+
+// parseOptionalDate parses an optional YYYY-MM-DD column of the transaction into RFC3339.
+// Returns an empty string when the column is missing or empty.
+func parseOptionalDate(transaction map[string]interface{}, key string) (string, error) {
+	value, ok := transaction[key].(string)
+	if !ok || strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return date.Format(time.RFC3339), nil
+}
+
+// formatTimeRange renders a start/end pair for log output, showing open-ended ranges with "..."
+func formatTimeRange(startISO, endISO string) string {
+	if endISO == "" {
+		endISO = "..."
+	}
+	return fmt.Sprintf("%s/%s", startISO, endISO)
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"orgchart_nexoan/models"
+)
+
+// Department custody
+// Departments (and the other institutions held by ministers) change hands through MoveDepartment and when
+// their minister is renamed, merged or split. The custody history of a department lists every minister
+// that held it; nested units list the institutions holding them. A department carried over by a rename stays in the same hands, so ministers renamed into each
+// other are collapsed into one custody period; a merge or split hands the department over.
+
+// GetDepartmentCustody returns the ministers that held an institution, oldest first
+func (c *Client) GetDepartmentCustody(departmentID string) (*models.DepartmentCustody, error) {
+	return newSnapshotReader(c, "", "").custody(departmentID)
+}
+
+// GetDepartmentCustodyByName returns the custody history of every institution with the given name
+func (c *Client) GetDepartmentCustodyByName(name string) ([]models.DepartmentCustody, error) {
+	reader := newSnapshotReader(c, "", "")
+	var histories []models.DepartmentCustody
+	for _, institutionType := range InstitutionTypes {
+		results, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: institutionType,
+			},
+			Name: name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %s '%s': %w", institutionType, name, err)
+		}
+		for _, result := range results {
+			reader.entities[result.ID] = result
+			history, err := reader.custody(result.ID)
+			if err != nil {
+				return nil, err
+			}
+			histories = append(histories, *history)
+		}
+	}
+
+	if len(histories) == 0 {
+		return nil, fmt.Errorf("institution named '%s' %w", name, ErrNotFound)
+	}
+	return histories, nil
+}
+
+// GetMostHandedOver returns the custody histories of the institutions that changed hands most, most
+// handovers first. limit caps the number of institutions returned (all when zero or less).
+func (c *Client) GetMostHandedOver(limit int) ([]models.DepartmentCustody, error) {
+	reader := newSnapshotReader(c, "", "")
+	var histories []models.DepartmentCustody
+	for _, institutionType := range InstitutionTypes {
+		results, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: institutionType,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %s entities: %w", institutionType, err)
+		}
+		for _, result := range results {
+			reader.entities[result.ID] = result
+			history, err := reader.custody(result.ID)
+			if err != nil {
+				return nil, err
+			}
+			if history.Handovers > 0 {
+				histories = append(histories, *history)
+			}
+		}
+	}
+
+	sort.SliceStable(histories, func(i, j int) bool {
+		if histories[i].Handovers != histories[j].Handovers {
+			return histories[i].Handovers > histories[j].Handovers
+		}
+		return histories[i].Name < histories[j].Name
+	})
+	if limit > 0 && len(histories) > limit {
+		histories = histories[:limit]
+	}
+	return histories, nil
+}
+
+// custody builds the custody history of an institution from the relationships holding it. Nested units are
+// held by institutions through AS_UNIT, their holders are listed like ministers.
+func (r *snapshotReader) custody(departmentID string) (*models.DepartmentCustody, error) {
+	department, err := r.entity(departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var relations []models.Relationship
+	for _, relType := range holdingRelTypes(department.Kind.Minor) {
+		holdings, err := r.client.GetRelatedEntities(departmentID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, departmentID, err)
+		}
+		relations = append(relations, holdings...)
+	}
+	sort.SliceStable(relations, func(i, j int) bool {
+		return relations[i].StartTime < relations[j].StartTime
+	})
+
+	var custodians []models.Custody
+	for _, rel := range relations {
+		minister, err := r.entity(rel.RelatedEntityID)
+		if err != nil {
+			return nil, err
+		}
+		presidents, err := r.presidentsBetween(minister, rel.StartTime, rel.EndTime, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// A department carried over by a rename stays with the renamed minister
+		if n := len(custodians); n > 0 {
+			last := &custodians[n-1]
+			renamed, err := r.renamedOn(last.MinisterIDs[len(last.MinisterIDs)-1], minister.ID, rel.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			if renamed && last.EndTime == rel.StartTime {
+				last.MinisterIDs = append(last.MinisterIDs, minister.ID)
+				last.Ministers = append(last.Ministers, minister.Name)
+				last.EndTime = rel.EndTime
+				last.Presidents = appendMissing(last.Presidents, presidents...)
+				continue
+			}
+		}
+
+		custodians = append(custodians, models.Custody{
+			MinisterIDs: []string{minister.ID},
+			Ministers:   []string{minister.Name},
+			Presidents:  presidents,
+			StartTime:   rel.StartTime,
+			EndTime:     rel.EndTime,
+		})
+	}
+
+	history := &models.DepartmentCustody{
+		ID:         department.ID,
+		Name:       department.Name,
+		Type:       department.Kind.Minor,
+		Custodians: custodians,
+	}
+	if len(custodians) > 1 {
+		history.Handovers = len(custodians) - 1
+	}
+	return history, nil
+}
+
+// presidentsBetween returns the presidents a minister, or an institution holding a nested unit, was held by
+// at some point of a period (RFC3339, open ended when endISO is empty), in order. Institutions are followed up
+// to their ministers for the part of the period they were held.
+func (r *snapshotReader) presidentsBetween(holder models.SearchResult, startISO, endISO string, depth int) ([]string, error) {
+	if depth >= maxUnitDepth {
+		return nil, nil
+	}
+
+	var presidents []string
+	for _, relType := range holdingRelTypes(holder.Kind.Minor) {
+		relations, err := r.client.GetRelatedEntities(holder.ID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, holder.ID, err)
+		}
+		sort.SliceStable(relations, func(i, j int) bool {
+			return relations[i].StartTime < relations[j].StartTime
+		})
+
+		for _, rel := range relations {
+			if (endISO != "" && rel.StartTime >= endISO) || (rel.EndTime != "" && rel.EndTime <= startISO) {
+				continue
+			}
+			parent, err := r.entity(rel.RelatedEntityID)
+			if err != nil {
+				return nil, err
+			}
+			if relType == "AS_MINISTER" {
+				presidents = appendMissing(presidents, parent.Name)
+				continue
+			}
+
+			heldEnd := endISO
+			if heldEnd == "" || (rel.EndTime != "" && rel.EndTime < heldEnd) {
+				heldEnd = rel.EndTime
+			}
+			nested, err := r.presidentsBetween(parent, maxString(startISO, rel.StartTime), heldEnd, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			presidents = appendMissing(presidents, nested...)
+		}
+	}
+	return presidents, nil
+}
+
+// renamedOn reports whether one minister (or institution) was renamed to another on the date
+func (r *snapshotReader) renamedOn(fromID, toID, dateISO string) (bool, error) {
+	relations, err := r.client.GetRelatedEntities(fromID, &models.Relationship{
+		Name:            "RENAMED_TO",
+		RelatedEntityID: toID,
+		Direction:       "OUTGOING",
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get RENAMED_TO relationships of %s: %w", fromID, err)
+	}
+	for _, rel := range relations {
+		if rel.StartTime == dateISO {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// appendMissing appends the values that are not in the list yet
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,421 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Org chart diffs
+// A diff compares the snapshots of two dates. Ministers and institutions that disappeared are paired with
+// the entities they were renamed, merged or split into (RENAMED_TO, MERGED_INTO and SPLIT_INTO relationships
+// starting between the two dates) instead of being reported as removed and added. Appointments carried over
+// to a renamed or merged entity are not reported, and people who left one position and took another are
+// reported as moved.
+
+// Change actions
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeRenamed   = "renamed"
+	ChangeMerged    = "merged"
+	ChangeSplit     = "split"
+	ChangeMoved     = "moved"
+	ChangeAppointed = "appointed"
+)
+
+// lineageRelTypes are the relationships from an entity to the entities that replaced it
+var lineageRelTypes = []string{"RENAMED_TO", "MERGED_INTO", "SPLIT_INTO"}
+
+// maxLineageDepth bounds how many renames or merges are followed from one entity
+const maxLineageDepth = 20
+
+// flatSnapshotNode is a snapshot node with its position in the tree
+type flatSnapshotNode struct {
+	models.SnapshotNode
+	ParentID   string
+	ParentName string
+	President  string
+}
+
+// flattenSnapshot lists the organisations (by ID) and the appointments of people in a snapshot
+func flattenSnapshot(snapshot *models.Snapshot) (map[string]flatSnapshotNode, []flatSnapshotNode) {
+	organisations := make(map[string]flatSnapshotNode)
+	var appointments []flatSnapshotNode
+
+	var walk func(node models.SnapshotNode, parent *models.SnapshotNode, president string)
+	walk = func(node models.SnapshotNode, parent *models.SnapshotNode, president string) {
+		if node.Relationship == "AS_PRESIDENT" {
+			president = node.Name
+			node.Role = "president"
+		}
+		flat := flatSnapshotNode{SnapshotNode: node, President: president}
+		flat.Children = nil
+		if parent != nil {
+			flat.ParentID = parent.ID
+			flat.ParentName = parent.Name
+		}
+
+		if node.Type == "citizen" {
+			appointments = append(appointments, flat)
+		} else if _, seen := organisations[node.ID]; !seen && parent != nil {
+			organisations[node.ID] = flat
+		}
+
+		for _, child := range node.Children {
+			walk(child, &node, president)
+		}
+	}
+	walk(snapshot.Government, nil, "")
+
+	return organisations, appointments
+}
+
+// GetDiff compares the org chart on two dates (RFC3339)
+func (c *Client) GetDiff(fromISO, toISO string) (*models.OrgChartDiff, error) {
+	if toISO <= fromISO {
+		return nil, fmt.Errorf("the end date %s must be after the start date %s", toISO, fromISO)
+	}
+
+	from, err := c.GetSnapshot(fromISO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot of %s: %w", fromISO, err)
+	}
+	to, err := c.GetSnapshot(toISO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot of %s: %w", toISO, err)
+	}
+
+	// Follow the successors of every organisation that disappeared
+	fromOrganisations, _ := flattenSnapshot(from)
+	toOrganisations, _ := flattenSnapshot(to)
+	successors := make(map[string][]models.Relationship)
+	var pending []string
+	for id := range fromOrganisations {
+		if _, ok := toOrganisations[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+	for depth := 0; depth < maxLineageDepth && len(pending) > 0; depth++ {
+		var next []string
+		for _, id := range pending {
+			if _, done := successors[id]; done {
+				continue
+			}
+			relations, err := c.getSuccessorRelationships(id)
+			if err != nil {
+				return nil, err
+			}
+			successors[id] = relations
+			for _, rel := range relations {
+				if _, ok := toOrganisations[rel.RelatedEntityID]; !ok {
+					next = append(next, rel.RelatedEntityID)
+				}
+			}
+		}
+		pending = next
+	}
+
+	return DiffSnapshots(from, to, successors), nil
+}
+
+// getSuccessorRelationships returns the RENAMED_TO, MERGED_INTO and SPLIT_INTO relationships from an entity
+func (c *Client) getSuccessorRelationships(entityID string) ([]models.Relationship, error) {
+	var successors []models.Relationship
+	for _, relType := range lineageRelTypes {
+		relations, err := c.GetRelatedEntities(entityID, &models.Relationship{
+			Name:      relType,
+			Direction: "OUTGOING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, entityID, err)
+		}
+		for _, rel := range relations {
+			// Relationships returned without a name are assumed to be of the queried type
+			if rel.Name == "" {
+				rel.Name = relType
+			}
+			successors = append(successors, rel)
+		}
+	}
+	return successors, nil
+}
+
+// DiffSnapshots compares two snapshots. successors holds the RENAMED_TO, MERGED_INTO and SPLIT_INTO
+// relationships of the entities of the first snapshot (and their successors) used to pair entities.
+func DiffSnapshots(from, to *models.Snapshot, successors map[string][]models.Relationship) *models.OrgChartDiff {
+	fromOrganisations, fromAppointments := flattenSnapshot(from)
+	toOrganisations, toAppointments := flattenSnapshot(to)
+	diff := &models.OrgChartDiff{From: from.Date, To: to.Date}
+
+	// Organisations that disappeared or appeared between the dates
+	var removed []string
+	for id := range fromOrganisations {
+		if _, ok := toOrganisations[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	added := make(map[string]bool)
+	for id := range toOrganisations {
+		if _, ok := fromOrganisations[id]; !ok {
+			added[id] = true
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return fromOrganisations[removed[i]].Name < fromOrganisations[removed[j]].Name
+	})
+
+	// Pair the removed organisations with their successors
+	replacedBy := make(map[string][]string) // old ID -> IDs in the second snapshot
+	merges := make(map[string][]string)     // merged ID -> old IDs
+	var mergeOrder []string
+	for _, id := range removed {
+		old := fromOrganisations[id]
+		relType, date, targets := findSuccessors(id, from.Date, to.Date, toOrganisations, successors)
+		if len(targets) == 0 {
+			diff.Changes = append(diff.Changes, models.OrgChartChange{
+				Action:    ChangeRemoved,
+				Type:      old.Type,
+				ID:        id,
+				Name:      old.Name,
+				OldParent: old.ParentName,
+				President: old.President,
+			})
+			continue
+		}
+		replacedBy[id] = targets
+
+		switch relType {
+		case "MERGED_INTO":
+			if _, seen := merges[targets[0]]; !seen {
+				mergeOrder = append(mergeOrder, targets[0])
+			}
+			merges[targets[0]] = append(merges[targets[0]], id)
+		case "SPLIT_INTO":
+			change := models.OrgChartChange{
+				Action:    ChangeSplit,
+				Type:      old.Type,
+				ID:        id,
+				Name:      old.Name,
+				President: old.President,
+				Date:      date,
+			}
+			for _, target := range targets {
+				change.To = append(change.To, toOrganisations[target].Name)
+			}
+			sort.Strings(change.To)
+			diff.Changes = append(diff.Changes, change)
+		default:
+			renamed := toOrganisations[targets[0]]
+			diff.Changes = append(diff.Changes, models.OrgChartChange{
+				Action:    ChangeRenamed,
+				Type:      renamed.Type,
+				ID:        renamed.ID,
+				Name:      renamed.Name,
+				From:      []string{old.Name},
+				President: renamed.President,
+				Date:      date,
+			})
+		}
+		for _, target := range targets {
+			delete(added, target)
+		}
+	}
+	for _, target := range mergeOrder {
+		merged := toOrganisations[target]
+		change := models.OrgChartChange{
+			Action:    ChangeMerged,
+			Type:      merged.Type,
+			ID:        merged.ID,
+			Name:      merged.Name,
+			President: merged.President,
+		}
+		for _, id := range merges[target] {
+			change.From = append(change.From, fromOrganisations[id].Name)
+			_, date, _ := findSuccessors(id, from.Date, to.Date, toOrganisations, successors)
+			if date > change.Date {
+				change.Date = date
+			}
+		}
+		sort.Strings(change.From)
+		diff.Changes = append(diff.Changes, change)
+	}
+
+	for id := range added {
+		node := toOrganisations[id]
+		diff.Changes = append(diff.Changes, models.OrgChartChange{
+			Action:    ChangeAdded,
+			Type:      node.Type,
+			ID:        id,
+			Name:      node.Name,
+			NewParent: node.ParentName,
+			President: node.President,
+			Date:      node.StartTime,
+		})
+	}
+
+	// sameParent reports whether a parent of the first snapshot is (or was replaced by) the given parent
+	sameParent := func(fromParentID, toParentID string) bool {
+		if fromParentID == toParentID {
+			return true
+		}
+		for _, target := range replacedBy[fromParentID] {
+			if target == toParentID {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Organisations held by a different parent
+	for id, node := range toOrganisations {
+		old, ok := fromOrganisations[id]
+		if !ok || sameParent(old.ParentID, node.ParentID) {
+			continue
+		}
+		diff.Changes = append(diff.Changes, models.OrgChartChange{
+			Action:    ChangeMoved,
+			Type:      node.Type,
+			ID:        id,
+			Name:      node.Name,
+			OldParent: old.ParentName,
+			NewParent: node.ParentName,
+			President: node.President,
+			Date:      node.StartTime,
+		})
+	}
+
+	// Appointments ended or started, ignoring the ones carried over to a replacing entity
+	matched := make([]bool, len(toAppointments))
+	var ended []flatSnapshotNode
+	for _, old := range fromAppointments {
+		found := false
+		for i, appointment := range toAppointments {
+			if !matched[i] && appointment.ID == old.ID && appointment.Role == old.Role && sameParent(old.ParentID, appointment.ParentID) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			ended = append(ended, old)
+		}
+	}
+	var started []flatSnapshotNode
+	for i, appointment := range toAppointments {
+		if !matched[i] {
+			started = append(started, appointment)
+		}
+	}
+
+	// A person who left one position and took another moved
+	for _, old := range ended {
+		moved := false
+		for i, appointment := range started {
+			if appointment.ID != old.ID {
+				continue
+			}
+			diff.Changes = append(diff.Changes, models.OrgChartChange{
+				Action:    ChangeMoved,
+				Type:      appointment.Type,
+				ID:        appointment.ID,
+				Name:      appointment.Name,
+				Role:      appointment.Role,
+				OldParent: old.ParentName,
+				NewParent: appointment.ParentName,
+				President: appointment.President,
+				Date:      appointment.StartTime,
+			})
+			started = append(started[:i], started[i+1:]...)
+			moved = true
+			break
+		}
+		if !moved {
+			diff.Changes = append(diff.Changes, models.OrgChartChange{
+				Action:    ChangeRemoved,
+				Type:      old.Type,
+				ID:        old.ID,
+				Name:      old.Name,
+				Role:      old.Role,
+				OldParent: old.ParentName,
+				President: old.President,
+			})
+		}
+	}
+	for _, appointment := range started {
+		diff.Changes = append(diff.Changes, models.OrgChartChange{
+			Action:    ChangeAppointed,
+			Type:      appointment.Type,
+			ID:        appointment.ID,
+			Name:      appointment.Name,
+			Role:      appointment.Role,
+			NewParent: appointment.ParentName,
+			President: appointment.President,
+			Date:      appointment.StartTime,
+		})
+	}
+
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if changeTypeOrder(a) != changeTypeOrder(b) {
+			return changeTypeOrder(a) < changeTypeOrder(b)
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		return a.Name < b.Name
+	})
+
+	return diff
+}
+
+// findSuccessors follows the RENAMED_TO, MERGED_INTO and SPLIT_INTO relationships that started between the
+// two dates from an entity to the entities of the second snapshot. Returns the first relationship type
+// followed, the date of the last step and the successors found.
+func findSuccessors(id, fromISO, toISO string, toOrganisations map[string]flatSnapshotNode, successors map[string][]models.Relationship) (string, string, []string) {
+	relType, date := "", ""
+	var targets []string
+	visited := map[string]bool{id: true}
+	pending := []string{id}
+	for depth := 0; depth < maxLineageDepth && len(pending) > 0; depth++ {
+		var next []string
+		for _, current := range pending {
+			for _, rel := range successors[current] {
+				if rel.StartTime <= fromISO || rel.StartTime > toISO || visited[rel.RelatedEntityID] {
+					continue
+				}
+				visited[rel.RelatedEntityID] = true
+				if relType == "" {
+					relType = rel.Name
+				}
+				if rel.StartTime > date {
+					date = rel.StartTime
+				}
+				if _, ok := toOrganisations[rel.RelatedEntityID]; ok {
+					targets = append(targets, rel.RelatedEntityID)
+				} else {
+					next = append(next, rel.RelatedEntityID)
+				}
+			}
+		}
+		pending = next
+	}
+	sort.Strings(targets)
+	return relType, date, targets
+}
+
+// changeTypeOrder orders changes by the kind of entity: presidents, ministers, institutions, then people
+func changeTypeOrder(change models.OrgChartChange) int {
+	switch {
+	case change.Type == "citizen" && strings.EqualFold(change.Role, "president"):
+		return 0
+	case change.Type == "minister":
+		return 1
+	case change.Type == "citizen":
+		return 3
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,394 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Document metadata keys
+// Document CSVs may carry url, description (or desc), gazette_number, part, language and pages columns.
+// Non-empty values are stored in the metadata of the Document entity and overwritten when the same
+// document is ingested again.
+const (
+	DocumentURLKey           = "url"
+	DocumentDescriptionKey   = "description"
+	DocumentGazetteNumberKey = "gazette_number"
+	DocumentPartKey          = "part"
+	DocumentLanguageKey      = "language"
+	DocumentPagesKey         = "pages"
+	DocumentDateKey          = "published"
+)
+
+// documentMetadata builds the metadata of a Document entity from a documents CSV row. The gazette number
+// defaults to the document name.
+func documentMetadata(transaction map[string]interface{}, child, dateISO string) ([]models.MetadataEntry, error) {
+	column := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := transaction[key].(string); ok && strings.TrimSpace(value) != "" {
+				return strings.TrimSpace(value)
+			}
+		}
+		return ""
+	}
+
+	gazetteNumber := column(DocumentGazetteNumberKey)
+	if gazetteNumber == "" {
+		gazetteNumber = child
+	}
+
+	pages := column(DocumentPagesKey)
+	if pages != "" {
+		count, err := strconv.Atoi(pages)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid page count '%s' for document %s", pages, child)
+		}
+	}
+
+	values := []struct {
+		key   string
+		value string
+	}{
+		{DocumentGazetteNumberKey, gazetteNumber},
+		{DocumentDateKey, dateISO},
+		{DocumentURLKey, column(DocumentURLKey)},
+		{DocumentDescriptionKey, column(DocumentDescriptionKey, "desc")},
+		{DocumentPartKey, column(DocumentPartKey)},
+		{DocumentLanguageKey, strings.ToLower(column(DocumentLanguageKey))},
+		{DocumentPagesKey, pages},
+	}
+
+	var metadata []models.MetadataEntry
+	for _, v := range values {
+		if v.value == "" {
+			continue
+		}
+		metadata = append(metadata, models.MetadataEntry{Key: v.key, Value: v.value})
+	}
+	return metadata, nil
+}
+
+// resolveDocumentParents resolves the parents of a document row. parent may list several entities
+// ("[A; B]") and parent_type gives either one type for all of them or one type per parent.
+func (c *Client) resolveDocumentParents(transaction map[string]interface{}, parent, parentType, dateISO string) ([]string, error) {
+	parents := parseNameList(parent)
+	parentTypes := parseNameList(parentType)
+	if len(parents) == 0 {
+		return nil, fmt.Errorf("parent is required and must be a string")
+	}
+	if len(parentTypes) != 1 && len(parentTypes) != len(parents) {
+		return nil, fmt.Errorf("expected 1 or %d parent types, got %d", len(parents), len(parentTypes))
+	}
+
+	presidentName, _ := transaction["president"].(string)
+	var parentIDs []string
+	for i, name := range parents {
+		entityType := parentTypes[0]
+		if len(parentTypes) > 1 {
+			entityType = parentTypes[i]
+		}
+		parentID, err := c.resolveEntityID(name, entityType, presidentName, dateISO)
+		if err != nil {
+			return nil, fmt.Errorf("parent entity not found: %s (%s): %w", name, entityType, err)
+		}
+		parentIDs = append(parentIDs, parentID)
+	}
+	return parentIDs, nil
+}
+
+// GetDocumentMetadata returns the publication details stored on a Document entity
+func (c *Client) GetDocumentMetadata(documentID string) (models.DocumentMetadata, error) {
+	metadata, err := c.GetEntityMetadata(documentID)
+	if err != nil {
+		return models.DocumentMetadata{}, fmt.Errorf("failed to get document metadata: %w", err)
+	}
+
+	value := func(key string) string {
+		raw, ok := metadata[key]
+		if !ok || raw == nil {
+			return ""
+		}
+		if s, ok := raw.(string); ok {
+			return s
+		}
+		return fmt.Sprint(raw)
+	}
+
+	document := models.DocumentMetadata{
+		ID:            documentID,
+		GazetteNumber: value(DocumentGazetteNumberKey),
+		Date:          value(DocumentDateKey),
+		URL:           value(DocumentURLKey),
+		Description:   value(DocumentDescriptionKey),
+		Part:          value(DocumentPartKey),
+		Language:      value(DocumentLanguageKey),
+	}
+	if pages := value(DocumentPagesKey); pages != "" {
+		document.Pages, err = strconv.Atoi(pages)
+		if err != nil {
+			return models.DocumentMetadata{}, fmt.Errorf("invalid page count '%s' on document %s", pages, documentID)
+		}
+	}
+
+	return document, nil
+}
+
+// Document lifecycle
+// A gazette can amend, correct (a corrigendum) or supersede an earlier gazette. The relationship is owned by
+// the newer document and starts on the date the change takes effect. A superseded gazette is no longer in
+// force from that date, while amended and corrected gazettes stay in force together with the documents
+// changing them.
+const (
+	AmendsRelType     = "AMENDS"
+	CorrectsRelType   = "CORRECTS"
+	SupersedesRelType = "SUPERSEDES"
+)
+
+// documentLifecycleRelTypes maps the document file types to the relationship they create
+var documentLifecycleRelTypes = map[string]string{
+	"AMENDS":     AmendsRelType,
+	"CORRECTS":   CorrectsRelType,
+	"SUPERSEDES": SupersedesRelType,
+}
+
+// maxDocumentChain bounds the number of documents followed when resolving a chain of instruments
+const maxDocumentChain = 100
+
+// AddDocumentLifecycle links a document to the earlier document it amends, corrects or supersedes, as given
+// by the file type of the transaction. Columns: transaction_id, document, target and date (the date the
+// change takes effect). Both documents must already be loaded.
+func (c *Client) AddDocumentLifecycle(transaction map[string]interface{}) error {
+	fileType, _ := transaction["file_type"].(string)
+	relType, ok := documentLifecycleRelTypes[fileType]
+	if !ok {
+		return fmt.Errorf("unknown document transaction type: %s", fileType)
+	}
+
+	document, _ := transaction["document"].(string)
+	target, _ := transaction["target"].(string)
+	document = strings.TrimSpace(document)
+	target = strings.TrimSpace(target)
+	if document == "" || target == "" {
+		return fmt.Errorf("document and target are required")
+	}
+	if document == target {
+		return fmt.Errorf("document %s cannot %s itself", document, strings.ToLower(relType))
+	}
+
+	dateStr, ok := transaction["date"].(string)
+	if !ok || dateStr == "" {
+		return fmt.Errorf("date is required and must be a string")
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	documentID, err := c.findSourceDocument(document)
+	if err != nil {
+		return err
+	}
+	if documentID == "" {
+		return fmt.Errorf("document not found: %s", document)
+	}
+	targetID, err := c.findSourceDocument(target)
+	if err != nil {
+		return err
+	}
+	if targetID == "" {
+		return fmt.Errorf("target document not found: %s", target)
+	}
+
+	// A document cannot supersede a document that (indirectly) supersedes it
+	if relType == SupersedesRelType {
+		supersededBy, err := c.isSupersededBy(documentID, targetID)
+		if err != nil {
+			return err
+		}
+		if supersededBy {
+			return fmt.Errorf("document %s cannot supersede %s because it is superseded by it", document, target)
+		}
+	}
+
+	// Loading the same transaction again does not add a second relationship
+	existing, err := c.GetRelatedEntities(documentID, &models.Relationship{
+		Name:            relType,
+		RelatedEntityID: targetID,
+		Direction:       "OUTGOING",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get %s relationships: %w", relType, err)
+	}
+	for _, rel := range existing {
+		if rel.StartTime == dateISO {
+			return nil
+		}
+	}
+
+	return c.createRelationship(documentID, targetID, relType, dateISO)
+}
+
+// isSupersededBy reports whether the document is superseded, directly or through other documents, by the
+// given document
+func (c *Client) isSupersededBy(documentID, byID string) (bool, error) {
+	visited := map[string]bool{}
+	pending := []string{documentID}
+	for len(pending) > 0 && len(visited) < maxDocumentChain {
+		currentID := pending[0]
+		pending = pending[1:]
+		if visited[currentID] {
+			continue
+		}
+		visited[currentID] = true
+
+		relations, err := c.GetRelatedEntities(currentID, &models.Relationship{
+			Name:      SupersedesRelType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to get %s relationships: %w", SupersedesRelType, err)
+		}
+		for _, rel := range relations {
+			if rel.RelatedEntityID == byID {
+				return true, nil
+			}
+			pending = append(pending, rel.RelatedEntityID)
+		}
+	}
+	return false, nil
+}
+
+// currentDocument follows the documents superseding the given document on the date and returns the one in
+// force, with the date it took effect (empty when the document itself is in force)
+func (c *Client) currentDocument(documentID, dateISO string) (string, string, error) {
+	effectiveDate := ""
+	for depth := 0; depth < maxDocumentChain; depth++ {
+		relations, err := c.GetRelatedEntities(documentID, &models.Relationship{
+			Name:      SupersedesRelType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get %s relationships: %w", SupersedesRelType, err)
+		}
+
+		// The latest supersession in effect wins
+		var latest *models.Relationship
+		for _, rel := range relationshipsActiveAt(relations, dateISO) {
+			if latest == nil || rel.StartTime > latest.StartTime {
+				rel := rel
+				latest = &rel
+			}
+		}
+		if latest == nil {
+			return documentID, effectiveDate, nil
+		}
+		documentID = latest.RelatedEntityID
+		effectiveDate = latest.StartTime
+	}
+	return "", "", fmt.Errorf("document %s is superseded more than %d times", documentID, maxDocumentChain)
+}
+
+// documentName returns the gazette number (entity name) of a Document entity
+func (c *Client) documentName(documentID string) (string, error) {
+	results, err := c.SearchEntities(&models.SearchCriteria{ID: documentID})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for document entity: %w", err)
+	}
+	if len(results) == 0 {
+		return "", fmt.Errorf("document entity not found: %s", documentID)
+	}
+	return results[0].Name, nil
+}
+
+// GetDocumentsInForce returns the chain of instruments in force on the date (RFC3339) for a gazette: the
+// gazette itself or the latest document superseding it, followed by the documents amending or correcting it
+// (and those documents' own amendments and corrections) in order of effect.
+func (c *Client) GetDocumentsInForce(gazetteNumber, dateISO string) ([]models.DocumentInForce, error) {
+	startID, err := c.findSourceDocument(gazetteNumber)
+	if err != nil {
+		return nil, err
+	}
+	if startID == "" {
+		return nil, fmt.Errorf("document not found: %s", gazetteNumber)
+	}
+
+	baseID, effectiveDate, err := c.currentDocument(startID, dateISO)
+	if err != nil {
+		return nil, err
+	}
+	baseName, err := c.documentName(baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []models.DocumentInForce{
+		{
+			ID:            baseID,
+			GazetteNumber: baseName,
+			EffectiveDate: effectiveDate,
+		},
+	}
+	if baseID != startID {
+		chain[0].Relationship = SupersedesRelType
+		chain[0].Target = gazetteNumber
+	}
+
+	type pendingDocument struct {
+		id   string
+		name string
+	}
+	visited := map[string]bool{baseID: true}
+	pending := []pendingDocument{{baseID, baseName}}
+	for len(pending) > 0 && len(visited) < maxDocumentChain {
+		current := pending[0]
+		pending = pending[1:]
+
+		var changes []models.Relationship
+		for _, relType := range []string{AmendsRelType, CorrectsRelType} {
+			relations, err := c.GetRelatedEntities(current.id, &models.Relationship{
+				Name:      relType,
+				Direction: "INCOMING",
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s relationships: %w", relType, err)
+			}
+			changes = append(changes, relationshipsActiveAt(relations, dateISO)...)
+		}
+
+		for _, rel := range changes {
+			// An amendment that was itself superseded is replaced by its successor
+			changeID, _, err := c.currentDocument(rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return nil, err
+			}
+			if visited[changeID] {
+				continue
+			}
+			visited[changeID] = true
+
+			changeName, err := c.documentName(changeID)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, models.DocumentInForce{
+				ID:            changeID,
+				GazetteNumber: changeName,
+				Relationship:  rel.Name,
+				Target:        current.name,
+				EffectiveDate: rel.StartTime,
+			})
+			pending = append(pending, pendingDocument{changeID, changeName})
+		}
+	}
+
+	sort.SliceStable(chain[1:], func(i, j int) bool {
+		return chain[1+i].EffectiveDate < chain[1+j].EffectiveDate
+	})
+
+	return chain, nil
+}
@@ -235,7 +235,7 @@ func (c *Client) AddOrgEntity(transaction map[string]interface{}, entityCounters
 
 	// Get the part before the first underscore for the prefix
 	prefixPart := strings.Split(transactionID, "_")[0]
-	prefix := fmt.Sprintf("%s_%s", prefixPart, strings.ToLower(childType[:3]))
+	prefix := fmt.Sprintf("%s_%s", prefixPart, entityIDCode(childType))
 	entityCounter := entityCounters[childType] + 1
 	newEntityID := fmt.Sprintf("%s_%d", prefix, entityCounter)
 
@@ -263,41 +263,44 @@ func (c *Client) AddOrgEntity(transaction map[string]interface{}, entityCounters
 		}
 		parentID = presidentEntity.ID
 
-	} else if childType == "department" {
-		// For departments, parent should be a minister, but we need to verify it's the correct minister
-		if parentType != "minister" {
-			return 0, fmt.Errorf("department must be attached to a minister, got parent_type: %s", parentType)
+	} else if IsInstitutionType(childType) {
+		// For departments and other institutions, parent should be a minister (AS_DEPARTMENT) or another
+		// institution the new one is a unit of (AS_UNIT), verified under the correct president
+		expectedRelType, err := institutionRelType(parentType)
+		if err != nil {
+			return 0, err
+		}
+		if relType != expectedRelType {
+			return 0, fmt.Errorf("a %s attached to a %s must use rel_type %s, got: %s", childType, parentType, expectedRelType, relType)
 		}
 
 		// Get president name from transaction
 		presidentName, ok := transaction["president"].(string)
 		if !ok || presidentName == "" {
-			return 0, fmt.Errorf("president name is required and must be a non-empty string when adding a department")
+			return 0, fmt.Errorf("president name is required and must be a non-empty string when adding a %s", childType)
 		}
 
-		// Check if a department with the same name already exists
-		existingDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		// Check if an institution of the same type and name already exists
+		existingResults, err := c.SearchEntities(&models.SearchCriteria{
 			Kind: &models.Kind{
 				Major: "Organisation",
-				Minor: "department",
+				Minor: childType,
 			},
 			Name: child,
 		})
 		if err != nil {
-			return 0, fmt.Errorf("failed to search for existing department: %w", err)
+			return 0, fmt.Errorf("failed to search for existing %s: %w", childType, err)
 		}
-		if len(existingDepartmentResults) > 0 {
-			return 0, fmt.Errorf("department with name '%s' already exists", child)
+		if len(existingResults) > 0 {
+			return 0, fmt.Errorf("%s with name '%s' already exists", childType, child)
 		}
 
-		// Use GetMinisterByPresident to ensure we get the correct minister under the correct president
-		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, parent, dateISO)
+		// Resolve the parent minister or institution under the correct president
+		parentID, err = c.getInstitutionParentID(presidentName, parent, parentType, dateISO)
 		if err != nil {
-			return 0, fmt.Errorf("failed to get parent minister entity: %w", err)
+			return 0, err
 		}
 
-		parentID = ministerEntity.ID
-
 	} else {
 		// For other entity types, use the original logic
 		majorType := "Organisation"
@@ -324,6 +327,15 @@ func (c *Client) AddOrgEntity(transaction map[string]interface{}, entityCounters
 		parentID = searchResults[0].ID
 	}
 
+	// Ministers carry their category and gazette position as attributes
+	attributes := []models.AttributeEntry{}
+	if childType == "minister" {
+		attributes, err = ministerClassification(transaction, child, dateISO)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Create the new child entity
 	childEntity := &models.Entity{
 		ID: newEntityID,
@@ -338,7 +350,7 @@ func (c *Client) AddOrgEntity(transaction map[string]interface{}, entityCounters
 			Value:     child,
 		},
 		Metadata:      []models.MetadataEntry{},
-		Attributes:    []models.AttributeEntry{},
+		Attributes:    attributes,
 		Relationships: []models.RelationshipEntry{},
 	}
 
@@ -380,6 +392,12 @@ func (c *Client) AddOrgEntity(transaction map[string]interface{}, entityCounters
 		return 0, fmt.Errorf("failed to update parent entity: %w", err)
 	}
 
+	// Link the new entity to the gazette it was taken from
+	err = c.recordSource(transaction, createdChild.ID, dateISO)
+	if err != nil {
+		return 0, err
+	}
+
 	return entityCounter, nil
 }
 
@@ -425,6 +443,19 @@ func (c *Client) TerminateOrgEntity(transaction map[string]interface{}) error {
 		}
 		parentID = ministerEntity.ID
 
+	} else if IsInstitutionType(parentType) {
+		// Parent is an institution holding a nested unit, need president context to get the correct one
+		presidentName, ok := transaction["president"].(string)
+		if !ok || presidentName == "" {
+			return fmt.Errorf("president name is required and must be a non-empty string when terminating %s relationships", parentType)
+		}
+
+		institutionEntity, err := c.getActiveInstitutionByPresident(presidentName, parent, parentType, dateISO)
+		if err != nil {
+			return fmt.Errorf("failed to get parent %s entity: %w", parentType, err)
+		}
+		parentID = institutionEntity.ID
+
 	} else {
 		// For other parent types, use the original logic
 		parentMajorType := "Organisation"
@@ -460,46 +491,37 @@ func (c *Client) TerminateOrgEntity(transaction map[string]interface{}) error {
 		}
 		childID = ministerEntity.ID
 
-	} else if childType == "department" {
-		// Child is a department, need to find it under the correct minister
-		presidentName, ok := transaction["president"].(string)
-		if !ok || presidentName == "" {
-			return fmt.Errorf("president name is required and must be a non-empty string when terminating department relationships")
-		}
-
-		// First get the minister that should have this department
-		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, parent, dateISO)
+	} else if IsInstitutionType(childType) {
+		// Child is a department or other institution, find it under the parent resolved above
+		// (a minister, or an institution for nested units)
+		holdingRelType, err := institutionRelType(parentType)
 		if err != nil {
-			return fmt.Errorf("failed to get minister for department termination: %w", err)
+			return err
 		}
-
-		// Then find the department under this minister
-		departmentRelations, err := c.GetRelatedEntities(ministerEntity.ID, &models.Relationship{
-			Name: "AS_DEPARTMENT",
+		institutionRelations, err := c.GetRelatedEntities(parentID, &models.Relationship{
+			Name: holdingRelType,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to get minister's department relationships: %w", err)
+			return fmt.Errorf("failed to get %s relationships of '%s': %w", holdingRelType, parent, err)
 		}
 
-		// Find the department with the matching name
-		var foundDepartmentID string
-		for _, rel := range departmentRelations {
-			if rel.EndTime == "" { // Only active relationships
-				departmentResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
-				if err != nil || len(departmentResults) == 0 {
-					continue
-				}
-				if departmentResults[0].Name == child {
-					foundDepartmentID = rel.RelatedEntityID
-					break
-				}
+		// Find the institution with the matching name and type
+		var foundInstitutionID string
+		for _, rel := range activeRelationships(institutionRelations) {
+			institutionResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+			if err != nil || len(institutionResults) == 0 {
+				continue
+			}
+			if institutionResults[0].Name == child && institutionResults[0].Kind.Minor == childType {
+				foundInstitutionID = rel.RelatedEntityID
+				break
 			}
 		}
 
-		if foundDepartmentID == "" {
-			return fmt.Errorf("department '%s' not found under minister '%s'", child, parent)
+		if foundInstitutionID == "" {
+			return fmt.Errorf("%s '%s' not found under %s '%s'", childType, child, parentType, parent)
 		}
-		childID = foundDepartmentID
+		childID = foundInstitutionID
 
 	} else {
 		// For other child types, use the original logic
@@ -593,24 +615,20 @@ func (c *Client) TerminateOrgEntity(transaction map[string]interface{}) error {
 		return fmt.Errorf("failed to terminate relationship: %w", err)
 	}
 
+	// Link the terminated entity to the gazette the termination was taken from
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
 	// If we're terminating a minister, also terminate any active people assigned to it
 	if childType == "minister" {
-		// Get all active people relationships from the minister
-		ministerPeopleRelations, err := c.GetRelatedEntities(childID, &models.Relationship{
-			Name: "AS_APPOINTED",
-		})
+		// Get all active people relationships (of any role) from the minister
+		activePeopleRelations, err := c.getActiveAppointments(childID, "minister")
 		if err != nil {
 			return fmt.Errorf("failed to get minister's people relationships: %w", err)
 		}
 
-		// Find active people relationships (EndTime == "")
-		var activePeopleRelations []models.Relationship
-		for _, rel := range ministerPeopleRelations {
-			if rel.EndTime == "" {
-				activePeopleRelations = append(activePeopleRelations, rel)
-			}
-		}
-
 		// Terminate each active person relationship
 		for _, rel := range activePeopleRelations {
 			terminatePersonRel := &models.Entity{
@@ -630,6 +648,11 @@ func (c *Client) TerminateOrgEntity(transaction map[string]interface{}) error {
 			if err != nil {
 				return fmt.Errorf("failed to terminate person relationship: %w", err)
 			}
+
+			err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -637,13 +660,31 @@ func (c *Client) TerminateOrgEntity(transaction map[string]interface{}) error {
 }
 
 // MoveDepartment moves a department from one minister to another
-// MoveDepartment moves a department to a new minister
+// MoveDepartment moves a department to a new minister.
+// Other institution types are moved the same way, given by the "type" column. The optional "new_parent_type"
+// column (default minister) moves an institution below another institution as a nested unit.
 func (c *Client) MoveDepartment(transaction map[string]interface{}) error {
 	// Extract details from the transaction
 	newParent := transaction["new_parent"].(string)
 	child := transaction["child"].(string)
 	dateStr := transaction["date"].(string)
 
+	childType := InstitutionDepartment
+	if value, ok := transaction["type"].(string); ok && strings.TrimSpace(value) != "" {
+		childType = strings.TrimSpace(value)
+	}
+	if !IsInstitutionType(childType) {
+		return fmt.Errorf("unknown institution type: %s", childType)
+	}
+	newParentType := "minister"
+	if value, ok := transaction["new_parent_type"].(string); ok && strings.TrimSpace(value) != "" {
+		newParentType = strings.TrimSpace(value)
+	}
+	newRelType, err := institutionRelType(newParentType)
+	if err != nil {
+		return err
+	}
+
 	// Parse the date
 	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
 	if err != nil {
@@ -655,56 +696,21 @@ func (c *Client) MoveDepartment(transaction map[string]interface{}) error {
 	departmentResults, err := c.SearchEntities(&models.SearchCriteria{
 		Kind: &models.Kind{
 			Major: "Organisation",
-			Minor: "department",
+			Minor: childType,
 		},
 		Name: child,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to search for department: %w", err)
+		return fmt.Errorf("failed to search for %s: %w", childType, err)
 	}
 	if len(departmentResults) == 0 {
-		return fmt.Errorf("department '%s' not found", child)
+		return fmt.Errorf("%s '%s' not found", childType, child)
 	}
 	if len(departmentResults) > 1 {
-		return fmt.Errorf("multiple departments found with name '%s'", child)
+		return fmt.Errorf("multiple %s entities found with name '%s'", childType, child)
 	}
 	departmentID := departmentResults[0].ID
 
-	// Check for active incoming relationships to this department
-	// Get all relationships where this department is the target
-	departmentRelations, err := c.GetRelatedEntities(departmentID, &models.Relationship{
-		Name: "AS_DEPARTMENT",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to get department relationships: %w", err)
-	}
-
-	// Look for active AS_DEPARTMENT relationships coming into this department
-	for _, rel := range departmentRelations {
-		if rel.EndTime == "" {
-			// Found an active relationship - terminate it directly
-			// We have the minister ID (rel.RelatedEntityID) and can terminate the relationship directly
-			terminateRelationship := &models.Entity{
-				ID: rel.RelatedEntityID, // This is the minister ID
-				Relationships: []models.RelationshipEntry{
-					{
-						Key: rel.ID,
-						Value: models.Relationship{
-							EndTime: dateISO,
-							ID:      rel.ID,
-						},
-					},
-				},
-			}
-
-			_, err = c.UpdateEntity(rel.RelatedEntityID, terminateRelationship)
-			if err != nil {
-				return fmt.Errorf("failed to terminate old relationship: %w", err)
-			}
-			// Continue to terminate all active relationships, don't break
-		}
-	}
-
 	// Get the new minister entity ID by president
 	// We need the president name to get the correct minister
 	newPresidentName, ok := transaction["new_president_name"].(string)
@@ -712,38 +718,62 @@ func (c *Client) MoveDepartment(transaction map[string]interface{}) error {
 		return fmt.Errorf("new_president_name is required and must be a non-empty string")
 	}
 
-	newMinisterEntity, err := c.GetActiveMinisterByPresident(newPresidentName, newParent, dateISO)
+	newParentID, err := c.getInstitutionParentID(newPresidentName, newParent, newParentType, dateISO)
 	if err != nil {
-		return fmt.Errorf("failed to get new minister '%s' under president '%s': %w", newParent, newPresidentName, err)
+		return fmt.Errorf("failed to get new %s '%s' under president '%s': %w", newParentType, newParent, newPresidentName, err)
 	}
-	newMinisterID := newMinisterEntity.ID
 
-	// Create new AS_DEPARTMENT relationship from new minister to department
-	// Use transaction ID and timestamp to ensure unique relationship ID
-	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
-	uniqueRelationshipID := fmt.Sprintf("%s_%s_%s", newMinisterID, departmentID, currentTimestamp)
+	// A unit cannot be moved below itself or one of its own units
+	if newParentType != "minister" {
+		nested, err := c.isNestedUnder(newParentID, departmentID)
+		if err != nil {
+			return err
+		}
+		if nested {
+			return fmt.Errorf("cannot move %s '%s' below '%s', which is one of its own units", childType, child, newParent)
+		}
+	}
 
-	newRelationship := &models.Entity{
-		ID: newMinisterID,
-		Relationships: []models.RelationshipEntry{
-			{
-				Key: uniqueRelationshipID,
-				Value: models.Relationship{
-					RelatedEntityID: departmentID,
-					StartTime:       dateISO,
-					EndTime:         "",
-					ID:              uniqueRelationshipID,
-					Name:            "AS_DEPARTMENT",
-				},
-			},
-		},
+	// Check for active incoming relationships to this department
+	// Get all relationships where this department is the target: the minister holding it, or the
+	// institution it is a unit of
+	departmentRelations, err := c.GetRelatedEntities(departmentID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get department relationships: %w", err)
+	}
+	unitRelations, err := c.GetRelatedEntities(departmentID, &models.Relationship{
+		Name:      "AS_UNIT",
+		Direction: "INCOMING",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get unit relationships: %w", err)
+	}
+
+	// Look for active relationships coming into this department
+	for _, rel := range activeRelationships(append(departmentRelations, unitRelations...)) {
+		// Found an active relationship - terminate it directly
+		// We have the parent ID (rel.RelatedEntityID) and can terminate the relationship directly
+		err = c.terminateRelationship(rel.RelatedEntityID, rel.ID, dateISO)
+		if err != nil {
+			return fmt.Errorf("failed to terminate old relationship: %w", err)
+		}
+		// Continue to terminate all active relationships, don't break
 	}
 
-	_, err = c.UpdateEntity(newMinisterID, newRelationship)
+	// Create new relationship from the new parent to the department
+	err = c.createRelationship(newParentID, departmentID, newRelType, dateISO)
 	if err != nil {
 		return fmt.Errorf("failed to create new relationship: %w", err)
 	}
 
+	// Link the moved institution to the gazette the move was taken from
+	err = c.recordSource(transaction, departmentID, dateISO)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -788,6 +818,9 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 		"president":      presidentName,
 	}
 
+	// The renamed minister keeps the old minister's classification unless new values are given
+	c.inheritClassification(oldMinisterID, dateISO, transaction, addEntityTransaction)
+
 	// Create the new minister
 	newMinisterCounter, err := c.AddOrgEntity(addEntityTransaction, entityCounters)
 	if err != nil {
@@ -836,9 +869,11 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 			"old_parent":         oldName,
 			"new_parent":         newName,
 			"child":              departmentResults[0].Name,
+			"type":               departmentResults[0].Kind.Minor,
 			"date":               dateStr,
 			"new_president_name": presidentName,
 			"old_president_name": presidentName,
+			"transaction_id":     transactionID,
 		}
 
 		err = c.MoveDepartment(moveTransaction)
@@ -848,23 +883,13 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 	}
 
 	// Find and move active person connected to old minister to new minister
-	// Get all active people relationships from the old minister
-	oldMinisterPeopleRelations, err := c.GetRelatedEntities(oldMinisterID, &models.Relationship{
-		Name: "AS_APPOINTED",
-	})
+	// Get all active people relationships (of any role) from the old minister
+	activePeopleRelations, err := c.getActiveAppointments(oldMinisterID, "minister")
 	if err != nil {
 		return 0, fmt.Errorf("failed to get old minister's people relationships: %w", err)
 	}
 
-	// Find active people relationships (EndTime == "")
-	var activePeopleRelations []models.Relationship
-	for _, rel := range oldMinisterPeopleRelations {
-		if rel.EndTime == "" {
-			activePeopleRelations = append(activePeopleRelations, rel)
-		}
-	}
-
-	// Move each active person to the new minister
+	// Move each active person to the new minister, keeping their role
 	for _, rel := range activePeopleRelations {
 		// Create new relationship between new minister and person
 		currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
@@ -880,7 +905,7 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 						StartTime:       dateISO,
 						EndTime:         "",
 						ID:              uniqueRelationshipID,
-						Name:            "AS_APPOINTED",
+						Name:            rel.Name,
 					},
 				},
 			},
@@ -909,6 +934,11 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 		if err != nil {
 			return 0, fmt.Errorf("failed to terminate old person relationship: %w", err)
 		}
+
+		err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	// Terminate the old minister's relationship with the president directly
@@ -989,19 +1019,28 @@ func (c *Client) RenameMinister(transaction map[string]interface{}, entityCounte
 	return newMinisterCounter, nil
 }
 
-// RenameDepartment renames a department and transfers all its people relationships to the new department
+// RenameDepartment renames a department and transfers all its people relationships to the new department.
+// Other institution types are renamed the same way, given by the "type" column. The renamed institution stays
+// under the same parent (minister or institution) and keeps its nested units.
 func (c *Client) RenameDepartment(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
 	// Extract details from the transaction
 	oldName := transaction["old"].(string)
 	newName := transaction["new"].(string)
 	dateStr := transaction["date"].(string)
-	relType := "AS_DEPARTMENT"
 	transactionID := transaction["transaction_id"].(string)
 	presidentName, ok := transaction["president"].(string)
 	if !ok || presidentName == "" {
 		return 0, fmt.Errorf("president name is required and must be a non-empty string when renaming a department")
 	}
 
+	childType := InstitutionDepartment
+	if value, ok := transaction["type"].(string); ok && strings.TrimSpace(value) != "" {
+		childType = strings.TrimSpace(value)
+	}
+	if !IsInstitutionType(childType) {
+		return 0, fmt.Errorf("unknown institution type: %s", childType)
+	}
+
 	// Parse the date
 	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
 	if err != nil {
@@ -1013,15 +1052,15 @@ func (c *Client) RenameDepartment(transaction map[string]interface{}, entityCoun
 	oldDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
 		Kind: &models.Kind{
 			Major: "Organisation",
-			Minor: "department",
+			Minor: childType,
 		},
 		Name: oldName,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to search for old department: %w", err)
+		return 0, fmt.Errorf("failed to search for old %s: %w", childType, err)
 	}
 	if len(oldDepartmentResults) == 0 {
-		return 0, fmt.Errorf("old department not found: %s", oldName)
+		return 0, fmt.Errorf("old %s not found: %s", childType, oldName)
 	}
 	oldDepartmentID := oldDepartmentResults[0].ID
 
@@ -1029,34 +1068,33 @@ func (c *Client) RenameDepartment(transaction map[string]interface{}, entityCoun
 	existingDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
 		Kind: &models.Kind{
 			Major: "Organisation",
-			Minor: "department",
+			Minor: childType,
 		},
 		Name: newName,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to search for new department name: %w", err)
+		return 0, fmt.Errorf("failed to search for new %s name: %w", childType, err)
 	}
 
 	var newDepartmentID string
 	var newDepartmentCounter int
 
 	if len(existingDepartmentResults) > 0 {
-		// Check if the existing department has any active AS_DEPARTMENT relationships
+		// Check if the existing department has any active AS_DEPARTMENT or AS_UNIT relationships
 		existingDepartment := existingDepartmentResults[0]
 		existingDepartmentID := existingDepartment.ID
 
-		// Get all AS_DEPARTMENT relationships for this department
-		existingDepartmentRelations, err := c.GetRelatedEntities(existingDepartmentID, &models.Relationship{
-			Name: "AS_DEPARTMENT",
-		})
-		if err != nil {
-			return 0, fmt.Errorf("failed to get existing department relationships: %w", err)
-		}
-
-		// Check if any relationships are still active (EndTime == "")
 		hasActiveRelationships := false
-		for _, rel := range existingDepartmentRelations {
-			if rel.EndTime == "" {
+		for _, holdingRelType := range []string{"AS_DEPARTMENT", "AS_UNIT"} {
+			existingDepartmentRelations, err := c.GetRelatedEntities(existingDepartmentID, &models.Relationship{
+				Name: holdingRelType,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to get existing %s relationships: %w", childType, err)
+			}
+
+			// Check if any relationships are still active (EndTime == "")
+			if len(activeRelationships(existingDepartmentRelations)) > 0 {
 				hasActiveRelationships = true
 				break
 			}
@@ -1064,69 +1102,36 @@ func (c *Client) RenameDepartment(transaction map[string]interface{}, entityCoun
 
 		if hasActiveRelationships {
 			// Department exists and has active relationships, cannot proceed
-			return 0, fmt.Errorf("department with name '%s' already exists and has active relationships", newName)
-		} else {
-			// Department exists but all relationships are terminated, we can reuse it
-			newDepartmentID = existingDepartment.ID
-			//newDepartmentCounter = 0 // No new entity created, just reusing existing
+			return 0, fmt.Errorf("%s with name '%s' already exists and has active relationships", childType, newName)
 		}
-	} else {
-		// Department doesn't exist, we'll create a new one
-		newDepartmentID = ""
-		//newDepartmentCounter = 0
+		// Department exists but all relationships are terminated, we can reuse it
+		newDepartmentID = existingDepartment.ID
 	}
 
-	// Get all active relationships coming into this department
-	// The department can have multiple active relationships to different ministers from different presidents
-	departmentRelations, err := c.GetRelatedEntities(oldDepartmentID, &models.Relationship{
-		Name: "AS_DEPARTMENT",
-	})
+	// Find the minister (or parent institution for a nested unit) that holds this department under the
+	// specified president. The department can have multiple active relationships to different ministers
+	// from different presidents.
+	parent, err := c.getInstitutionParent(presidentName, oldDepartmentID, dateISO)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get department relationships: %w", err)
+		return 0, fmt.Errorf("no active parent relationship found for %s '%s' under president '%s': %w", childType, oldName, presidentName, err)
 	}
-
-	// Find the minister that has an active relationship to this department under the specified president
-	var ministerID string
-	var ministerName string
-	for _, rel := range departmentRelations {
-		if rel.EndTime == "" {
-			// This is an active relationship, check if the minister is under the correct president
-			ministerResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
-			if err != nil || len(ministerResults) == 0 {
-				continue
-			}
-			minister := ministerResults[0]
-
-			// Check if this minister is under the specified president
-			_, err = c.GetActiveMinisterByPresident(presidentName, minister.Name, dateISO)
-			if err == nil {
-				// Found the minister under the correct president
-				ministerID = minister.ID
-				ministerName = minister.Name
-				break
-			}
+	relType := parent.Relationship.Name
+	if relType == "" {
+		relType, err = institutionRelType(parent.Type)
+		if err != nil {
+			return 0, err
 		}
 	}
 
-	if ministerID == "" {
-		return 0, fmt.Errorf("no active minister relationship found for department '%s' under president '%s'", oldName, presidentName)
-	}
-
-	// Verify that this minister is under the correct president
-	// _, err = c.GetMinisterByPresident(presidentName, ministerName, dateISO)
-	// if err != nil {
-	// 	return 0, fmt.Errorf("minister '%s' not found under president '%s'", ministerName, presidentName)
-	// }
-
 	// Create new department or reuse existing inactive department
 	if newDepartmentID == "" {
-		// Create new department under the same minister
+		// Create new department under the same parent
 		addEntityTransaction := map[string]interface{}{
-			"parent":         ministerName,
+			"parent":         parent.Name,
 			"child":          newName,
 			"date":           dateStr,
-			"parent_type":    "minister",
-			"child_type":     "department",
+			"parent_type":    parent.Type,
+			"child_type":     childType,
 			"rel_type":       relType,
 			"transaction_id": transactionID,
 			"president":      presidentName,
@@ -1135,123 +1140,69 @@ func (c *Client) RenameDepartment(transaction map[string]interface{}, entityCoun
 		// Create the new department
 		newDepartmentCounter, err = c.AddOrgEntity(addEntityTransaction, entityCounters)
 		if err != nil {
-			return 0, fmt.Errorf("failed to create new department: %w", err)
+			return 0, fmt.Errorf("failed to create new %s: %w", childType, err)
 		}
 
 		// Get the new department's ID
 		newDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
 			Kind: &models.Kind{
 				Major: "Organisation",
-				Minor: "department",
+				Minor: childType,
 			},
 			Name: newName,
 		})
 		if err != nil {
-			return 0, fmt.Errorf("failed to search for new department: %w", err)
+			return 0, fmt.Errorf("failed to search for new %s: %w", childType, err)
 		}
 		if len(newDepartmentResults) == 0 {
-			return 0, fmt.Errorf("new department not found: %s", newName)
+			return 0, fmt.Errorf("new %s not found: %s", childType, newName)
 		}
 		if len(newDepartmentResults) > 1 {
-			return 0, fmt.Errorf("multiple departments found with name '%s'", newName)
+			return 0, fmt.Errorf("multiple %s entities found with name '%s'", childType, newName)
 		}
 		newDepartmentID = newDepartmentResults[0].ID
 	} else {
-		// Reusing existing inactive department - create the relationship with the minister
-		// Generate a unique relationship ID
-		newDepartmentCounter = entityCounters["department"]
-		currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
-		uniqueRelationshipID := fmt.Sprintf("%s_%s_%s", ministerID, newDepartmentID, currentTimestamp)
-
-		// Create the relationship between minister and the reactivated department
-		reactivateRelationship := &models.Entity{
-			ID: ministerID,
-			Relationships: []models.RelationshipEntry{
-				{
-					Key: uniqueRelationshipID,
-					Value: models.Relationship{
-						RelatedEntityID: newDepartmentID,
-						StartTime:       dateISO,
-						EndTime:         "",
-						ID:              uniqueRelationshipID,
-						Name:            relType,
-					},
-				},
-			},
+		// Reusing existing inactive department - create the relationship with the parent
+		newDepartmentCounter = entityCounters[childType]
+		err = c.createRelationship(parent.ID, newDepartmentID, relType, dateISO)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create relationship with reactivated %s: %w", childType, err)
 		}
-
-		_, err = c.UpdateEntity(ministerID, reactivateRelationship)
+		err = c.recordSource(transaction, newDepartmentID, dateISO)
 		if err != nil {
-			return 0, fmt.Errorf("failed to create relationship with reactivated department: %w", err)
+			return 0, err
 		}
 	}
 
-	// Terminate the old department's relationship with minister directly
-	// Get the specific existing relationship to this department
-	existingRelations, err := c.GetRelatedEntities(ministerID, &models.Relationship{
-		Name:            "AS_DEPARTMENT",
-		RelatedEntityID: oldDepartmentID,
-	})
+	// Terminate the old department's relationship with its parent directly
+	err = c.terminateRelationship(parent.ID, parent.Relationship.ID, dateISO)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get existing relationship: %w", err)
-	}
-
-	// Find the active relationship (no end time)
-	var existingRel *models.Relationship
-	for _, rel := range existingRelations {
-		if rel.EndTime == "" {
-			existingRel = &rel
-			break
-		}
-	}
-
-	if existingRel == nil {
-		return 0, fmt.Errorf("no active relationship found between minister '%s' and department '%s'", ministerID, oldDepartmentID)
-	}
-
-	// Terminate the relationship by updating it with the end time
-	terminateRelationship := &models.Entity{
-		ID: ministerID,
-		Relationships: []models.RelationshipEntry{
-			{
-				Key: existingRel.ID,
-				Value: models.Relationship{
-					EndTime: dateISO,
-					ID:      existingRel.ID,
-				},
-			},
-		},
+		return 0, fmt.Errorf("failed to terminate old %s's parent relationship: %w", childType, err)
 	}
 
-	_, err = c.UpdateEntity(ministerID, terminateRelationship)
+	// Move the nested units of the old department to the renamed one
+	unitRelations, err := c.GetRelatedEntities(oldDepartmentID, &models.Relationship{
+		Name:      "AS_UNIT",
+		Direction: "OUTGOING",
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to terminate old department's minister relationship: %w", err)
+		return 0, fmt.Errorf("failed to get unit relationships: %w", err)
 	}
-
-	// Create RENAMED_TO relationship
-	// Use transaction ID and current timestamp to ensure unique relationship ID
-	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
-	uniqueRelationshipID := fmt.Sprintf("%s_%s_%s", oldDepartmentID, newDepartmentID, currentTimestamp)
-
-	renameRelationship := &models.Entity{
-		ID: oldDepartmentID,
-		Relationships: []models.RelationshipEntry{
-			{
-				Key: uniqueRelationshipID,
-				Value: models.Relationship{
-					RelatedEntityID: newDepartmentID,
-					StartTime:       dateISO,
-					EndTime:         "",
-					ID:              uniqueRelationshipID,
-					Name:            "RENAMED_TO",
-				},
-			},
-		},
+	for _, rel := range activeRelationships(unitRelations) {
+		err = c.createRelationship(newDepartmentID, rel.RelatedEntityID, "AS_UNIT", dateISO)
+		if err != nil {
+			return 0, err
+		}
+		err = c.terminateRelationship(oldDepartmentID, rel.ID, dateISO)
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	_, err = c.UpdateEntity(oldDepartmentID, renameRelationship)
+	// Create RENAMED_TO relationship
+	err = c.createRelationship(oldDepartmentID, newDepartmentID, "RENAMED_TO", dateISO)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create RENAMED_TO relationship: %w", err)
+		return 0, err
 	}
 
 	return newDepartmentCounter, nil
@@ -1297,6 +1248,13 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 		"president":      presidentName,
 	}
 
+	// The merged minister takes the classification of the first old minister unless new values are given
+	firstOldMinister, err := c.GetActiveMinisterByPresident(presidentName, oldMinisters[0], dateISO)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get old minister: %w", err)
+	}
+	c.inheritClassification(firstOldMinister.ID, dateISO, transaction, addEntityTransaction)
+
 	newMinisterCounter, err := c.AddOrgEntity(addEntityTransaction, entityCounters)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create new minister: %w", err)
@@ -1351,9 +1309,11 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 				"old_parent":         oldMinister,
 				"new_parent":         newMinister,
 				"child":              departmentResults[0].Name,
+				"type":               departmentResults[0].Kind.Minor,
 				"date":               dateStr,
 				"new_president_name": presidentName,
 				"old_president_name": presidentName,
+				"transaction_id":     transactionID,
 			}
 
 			err = c.MoveDepartment(moveTransaction)
@@ -1363,21 +1323,11 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 		}
 
 		// 2. Terminate any active people assigned to the old minister - assume when merged, the people are no longer assigned to the old ministers
-		oldMinisterPeopleRelations, err := c.GetRelatedEntities(oldMinisterID, &models.Relationship{
-			Name: "AS_APPOINTED",
-		})
+		activePeopleRelations, err := c.getActiveAppointments(oldMinisterID, "minister")
 		if err != nil {
 			return 0, fmt.Errorf("failed to get old minister's people relationships: %w", err)
 		}
 
-		// Find active people relationships (EndTime == "")
-		var activePeopleRelations []models.Relationship
-		for _, rel := range oldMinisterPeopleRelations {
-			if rel.EndTime == "" {
-				activePeopleRelations = append(activePeopleRelations, rel)
-			}
-		}
-
 		// Terminate each active person relationship
 		for _, rel := range activePeopleRelations {
 			terminatePersonRel := &models.Entity{
@@ -1397,6 +1347,11 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 			if err != nil {
 				return 0, fmt.Errorf("failed to terminate person relationship: %w", err)
 			}
+
+			err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return 0, err
+			}
 		}
 
 		// 3. Terminate gov -> old minister relationship
@@ -1407,6 +1362,8 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 			"parent_type": "citizen",
 			"child_type":  "minister",
 			"rel_type":    "AS_MINISTER",
+			// Keep the transaction ID so the old minister is linked to the merge gazette
+			"transaction_id": transactionID,
 		}
 
 		err = c.TerminateOrgEntity(terminateGovTransaction)
@@ -1444,26 +1401,21 @@ func (c *Client) MergeMinisters(transaction map[string]interface{}, entityCounte
 	return newMinisterCounter, nil
 }
 
-// AddPersonEntity creates a new person entity and establishes its relationship with a parent entity.
-// Assumes the parent entity already exists.
-func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
+// MergeDepartments merges multiple departments into a single department under a minister.
+// The target department is created under the minister given in "new_parent" (or the minister currently
+// holding the first old department), or reused if a department with the new name already exists.
+func (c *Client) MergeDepartments(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
 	// Extract details from the transaction
-	parent := transaction["parent"].(string)
-	child := transaction["child"].(string)
+	oldDepartmentsStr := transaction["old"].(string)
+	newDepartment := transaction["new"].(string)
 	dateStr := transaction["date"].(string)
-	parentType := transaction["parent_type"].(string)
-	childType := transaction["child_type"].(string)
-	relType := transaction["rel_type"].(string)
 	transactionID := transaction["transaction_id"].(string)
+	relType := "AS_DEPARTMENT"
 
-	// Get president name if parent is a minister -> currently only supports adding people to ministers
-	var presidentName string
-	if parentType == "minister" {
-		var ok bool
-		presidentName, ok = transaction["president"].(string)
-		if !ok || presidentName == "" {
-			return 0, fmt.Errorf("president name is required and must be a non-empty string when adding a person to a minister")
-		}
+	// Validate president name is provided
+	presidentName, ok := transaction["president"].(string)
+	if !ok || presidentName == "" {
+		return 0, fmt.Errorf("president name is required and must be a non-empty string when merging departments")
 	}
 
 	// Parse the date
@@ -1473,70 +1425,536 @@ func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCount
 	}
 	dateISO := date.Format(time.RFC3339)
 
-	// Get the parent entity ID
-	var parentID string
+	oldDepartments := parseNameList(oldDepartmentsStr)
+	if len(oldDepartments) == 0 {
+		return 0, fmt.Errorf("at least one old department is required for a department merge")
+	}
 
-	if parentType == "minister" {
-		// Parent is a minister, need president context to get the correct minister
-		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, parent, dateISO)
+	// Resolve each old department and the minister currently holding it under the president
+	type mergeSource struct {
+		name         string
+		id           string
+		ministerID   string
+		ministerName string
+		relationship *models.Relationship
+	}
+	var sources []mergeSource
+	for _, oldDepartment := range oldDepartments {
+		departmentResults, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: "department",
+			},
+			Name: oldDepartment,
+		})
 		if err != nil {
-			return 0, fmt.Errorf("failed to get parent minister entity: %w", err)
+			return 0, fmt.Errorf("failed to search for old department: %w", err)
+		}
+		if len(departmentResults) == 0 {
+			return 0, fmt.Errorf("old department not found: %s", oldDepartment)
+		}
+		if len(departmentResults) > 1 {
+			return 0, fmt.Errorf("multiple departments found with name '%s'", oldDepartment)
+		}
+
+		ministerEntity, activeRel, err := c.getActiveMinisterOfDepartment(presidentName, departmentResults[0].ID, dateISO)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get minister of old department '%s': %w", oldDepartment, err)
+		}
+
+		sources = append(sources, mergeSource{
+			name:         oldDepartment,
+			id:           departmentResults[0].ID,
+			ministerID:   ministerEntity.ID,
+			ministerName: ministerEntity.Name.Value.(string),
+			relationship: activeRel,
+		})
+	}
+
+	// Get the target minister, defaulting to the minister of the first old department
+	targetMinisterName := sources[0].ministerName
+	if newParent, ok := transaction["new_parent"].(string); ok && strings.TrimSpace(newParent) != "" {
+		targetMinisterName = strings.TrimSpace(newParent)
+	}
+	targetMinister, err := c.GetActiveMinisterByPresident(presidentName, targetMinisterName, dateISO)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get target minister: %w", err)
+	}
+	targetMinisterID := targetMinister.ID
+
+	// Create the new department or reuse an existing one with the same name
+	existingDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "department",
+		},
+		Name: newDepartment,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to search for new department name: %w", err)
+	}
+
+	var newDepartmentID string
+	newDepartmentCounter := entityCounters["department"]
+
+	if len(existingDepartmentResults) > 0 {
+		newDepartmentID = existingDepartmentResults[0].ID
+
+		existingRelations, err := c.GetRelatedEntities(newDepartmentID, &models.Relationship{
+			Name: relType,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get existing department relationships: %w", err)
+		}
+
+		activeExistingRelations := activeRelationships(existingRelations)
+		if len(activeExistingRelations) == 0 {
+			// Department exists but all relationships are terminated, reactivate it under the target minister
+			err = c.createRelationship(targetMinisterID, newDepartmentID, relType, dateISO)
+			if err != nil {
+				return 0, fmt.Errorf("failed to create relationship with reactivated department: %w", err)
+			}
+		} else {
+			// Department is active, it can only absorb the others if it already sits under the target minister
+			heldByTarget := false
+			for _, rel := range activeExistingRelations {
+				if rel.RelatedEntityID == targetMinisterID {
+					heldByTarget = true
+					break
+				}
+			}
+			if !heldByTarget {
+				return 0, fmt.Errorf("department with name '%s' already exists and is active under another minister", newDepartment)
+			}
 		}
-		parentID = ministerEntity.ID
 	} else {
-		// For other parent types, use the original logic
-		searchCriteria := &models.SearchCriteria{
+		addEntityTransaction := map[string]interface{}{
+			"parent":         targetMinisterName,
+			"child":          newDepartment,
+			"date":           dateStr,
+			"parent_type":    "minister",
+			"child_type":     "department",
+			"rel_type":       relType,
+			"transaction_id": transactionID,
+			"president":      presidentName,
+		}
+
+		newDepartmentCounter, err = c.AddOrgEntity(addEntityTransaction, entityCounters)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create new department: %w", err)
+		}
+
+		newDepartmentResults, err := c.SearchEntities(&models.SearchCriteria{
 			Kind: &models.Kind{
 				Major: "Organisation",
-				Minor: parentType,
+				Minor: "department",
 			},
-			Name: parent,
+			Name: newDepartment,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to search for new department: %w", err)
+		}
+		if len(newDepartmentResults) != 1 {
+			return 0, fmt.Errorf("expected exactly one department with name '%s', found %d", newDepartment, len(newDepartmentResults))
 		}
+		newDepartmentID = newDepartmentResults[0].ID
+	}
 
-		searchResults, err := c.SearchEntities(searchCriteria)
+	for _, source := range sources {
+		// A department merged into itself keeps its existing relationships
+		if source.id == newDepartmentID {
+			continue
+		}
+
+		// 1. Re-point active people and documents of the old department to the new department
+		for _, linkType := range append(appointmentRelTypes("department"), "AS_DOCUMENT") {
+			linkRelations, err := c.GetRelatedEntities(source.id, &models.Relationship{
+				Name: linkType,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to get old department's %s relationships: %w", linkType, err)
+			}
+
+			for _, rel := range activeRelationships(linkRelations) {
+				err = c.createRelationship(newDepartmentID, rel.RelatedEntityID, linkType, dateISO)
+				if err != nil {
+					return 0, err
+				}
+				err = c.terminateRelationship(source.id, rel.ID, dateISO)
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		// 2. Terminate minister -> old department relationship
+		err = c.terminateRelationship(source.ministerID, source.relationship.ID, dateISO)
 		if err != nil {
-			return 0, fmt.Errorf("failed to search for parent entity: %w", err)
+			return 0, fmt.Errorf("failed to terminate old department '%s': %w", source.name, err)
 		}
 
-		if len(searchResults) == 0 {
-			return 0, fmt.Errorf("parent entity not found: %s", parent)
+		// 3. Create old department -> new department MERGED_INTO relationship
+		err = c.createRelationship(source.id, newDepartmentID, "MERGED_INTO", dateISO)
+		if err != nil {
+			return 0, err
 		}
+	}
 
-		parentID = searchResults[0].ID
+	return newDepartmentCounter, nil
+}
+
+// SplitMinister splits a minister into several new ministers under the same president.
+// Every active department of the old minister must be mapped to one of the new ministers in the
+// "departments" column (e.g. "[Department A=Minister X;Department B=Minister Y]"), and the "appointees"
+// column decides whether the people appointed to the old minister are terminated ("terminate") or
+// moved to one of the new ministers (the new minister's name).
+// One of the new ministers can keep the old minister's name. As ministers are looked up by name, that
+// minister is only created once the old minister is terminated, and its departments and appointees are
+// carried over by ID.
+func (c *Client) SplitMinister(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
+	// Extract details from the transaction
+	oldMinister := transaction["old"].(string)
+	newMinistersStr := transaction["new"].(string)
+	dateStr := transaction["date"].(string)
+	transactionID := transaction["transaction_id"].(string)
+
+	// Validate president name is provided
+	presidentName, ok := transaction["president"].(string)
+	if !ok || presidentName == "" {
+		return 0, fmt.Errorf("president name is required and must be a non-empty string")
 	}
 
-	// Check if person already exists (search across all person types)
-	personSearchCriteria := &models.SearchCriteria{
-		Kind: &models.Kind{
-			Major: "Person",
-		},
-		Name: child,
+	departmentsStr, ok := transaction["departments"].(string)
+	if !ok {
+		return 0, fmt.Errorf("departments mapping is required when splitting a minister")
+	}
+
+	appointees, ok := transaction["appointees"].(string)
+	appointees = strings.TrimSpace(appointees)
+	if !ok || appointees == "" {
+		return 0, fmt.Errorf("appointees is required and must be 'terminate' or the name of a new minister")
+	}
+
+	// Parse the date
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	newMinisters := parseNameList(newMinistersStr)
+	if len(newMinisters) < 2 {
+		return 0, fmt.Errorf("a split requires at least two new ministers, got %d", len(newMinisters))
+	}
+	isNewMinister := make(map[string]bool)
+	for _, newMinister := range newMinisters {
+		isNewMinister[newMinister] = true
+	}
+
+	// Parse the department -> new minister mapping
+	departmentMapping := make(map[string]string)
+	for _, entry := range parseNameList(departmentsStr) {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return 0, fmt.Errorf("invalid department mapping '%s', expected 'Department=Minister'", entry)
+		}
+		department := strings.TrimSpace(parts[0])
+		minister := strings.TrimSpace(parts[1])
+		if !isNewMinister[minister] {
+			return 0, fmt.Errorf("department '%s' is mapped to '%s' which is not one of the new ministers", department, minister)
+		}
+		departmentMapping[department] = minister
+	}
+
+	if appointees != "terminate" && !isNewMinister[appointees] {
+		return 0, fmt.Errorf("appointees must be 'terminate' or one of the new ministers, got '%s'", appointees)
+	}
+
+	// Get the old minister's ID
+	oldMinisterEntity, err := c.GetActiveMinisterByPresident(presidentName, oldMinister, dateISO)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get old minister: %w", err)
+	}
+	oldMinisterID := oldMinisterEntity.ID
+
+	// Get all active departments of the old minister and make sure each one has a destination
+	oldRelations, err := c.GetRelatedEntities(oldMinisterID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get old minister's relationships: %w", err)
+	}
+
+	var departmentNames []string
+	departmentTypes := make(map[string]string)
+	for _, rel := range activeRelationships(oldRelations) {
+		departmentResults, err := c.SearchEntities(&models.SearchCriteria{
+			ID: rel.RelatedEntityID,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to search for department: %w", err)
+		}
+		if len(departmentResults) == 0 {
+			return 0, fmt.Errorf("failed to find department with ID: %s", rel.RelatedEntityID)
+		}
+
+		departmentName := departmentResults[0].Name
+		if _, mapped := departmentMapping[departmentName]; !mapped {
+			return 0, fmt.Errorf("department '%s' of minister '%s' is not mapped to a new minister", departmentName, oldMinister)
+		}
+		departmentNames = append(departmentNames, departmentName)
+		departmentTypes[departmentName] = departmentResults[0].Kind.Minor
+	}
+
+	for department := range departmentMapping {
+		found := false
+		for _, departmentName := range departmentNames {
+			if departmentName == department {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("department '%s' is not an active department of minister '%s'", department, oldMinister)
+		}
+	}
+
+	// 1. Create the new ministers under the president, except the one keeping the old name
+	newMinisterCounter := entityCounters["minister"]
+	newMinisterIDs := make(map[string]string)
+	for _, newMinister := range newMinisters {
+		if newMinister == oldMinister {
+			continue
+		}
+		addEntityTransaction := map[string]interface{}{
+			"parent":         presidentName,
+			"child":          newMinister,
+			"date":           dateStr,
+			"parent_type":    "president",
+			"child_type":     "minister",
+			"rel_type":       "AS_MINISTER",
+			"transaction_id": transactionID,
+			"president":      presidentName,
+		}
+
+		newMinisterCounter, err = c.AddOrgEntity(addEntityTransaction, entityCounters)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create new minister '%s': %w", newMinister, err)
+		}
+		entityCounters["minister"] = newMinisterCounter
+
+		newMinisterEntity, err := c.GetActiveMinisterByPresident(presidentName, newMinister, dateISO)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get new minister: %w", err)
+		}
+		newMinisterIDs[newMinister] = newMinisterEntity.ID
+	}
+
+	// 2. Move each department to its new minister
+	for _, departmentName := range departmentNames {
+		if departmentMapping[departmentName] == oldMinister {
+			continue
+		}
+		moveTransaction := map[string]interface{}{
+			"old_parent":         oldMinister,
+			"new_parent":         departmentMapping[departmentName],
+			"child":              departmentName,
+			"type":               departmentTypes[departmentName],
+			"date":               dateStr,
+			"new_president_name": presidentName,
+			"old_president_name": presidentName,
+			"transaction_id":     transactionID,
+		}
+
+		err = c.MoveDepartment(moveTransaction)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move department: %w", err)
+		}
 	}
 
-	personResults, err := c.SearchEntities(personSearchCriteria)
+	// 3. Move the people appointed to the old minister, unless they are to be terminated
+	// (terminating the old minister below ends any appointment that is still active)
+	activePeopleRelations, err := c.getActiveAppointments(oldMinisterID, "minister")
 	if err != nil {
-		return 0, fmt.Errorf("failed to search for person entity: %w", err)
+		return 0, fmt.Errorf("failed to get old minister's people relationships: %w", err)
+	}
+	if appointees != "terminate" && appointees != oldMinister {
+		for _, rel := range activePeopleRelations {
+			err = c.createRelationship(newMinisterIDs[appointees], rel.RelatedEntityID, rel.Name, dateISO)
+			if err != nil {
+				return 0, err
+			}
+			err = c.terminateRelationship(oldMinisterID, rel.ID, dateISO)
+			if err != nil {
+				return 0, err
+			}
+			err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// 4. Terminate president -> old minister relationship (also terminates remaining appointments)
+	terminateTransaction := map[string]interface{}{
+		"parent":      presidentName,
+		"child":       oldMinister,
+		"date":        dateStr,
+		"parent_type": "citizen",
+		"child_type":  "minister",
+		"rel_type":    "AS_MINISTER",
+		// Keep the transaction ID so the old minister is linked to the split gazette
+		"transaction_id": transactionID,
 	}
 
-	if len(personResults) > 1 {
-		return 0, fmt.Errorf("multiple entities found for person: %s", child)
+	err = c.TerminateOrgEntity(terminateTransaction)
+	if err != nil {
+		return 0, fmt.Errorf("failed to terminate old minister's government relationship: %w", err)
 	}
 
+	// The new minister keeping the old name can now be created and resolved by name
+	if isNewMinister[oldMinister] {
+		addEntityTransaction := map[string]interface{}{
+			"parent":         presidentName,
+			"child":          oldMinister,
+			"date":           dateStr,
+			"parent_type":    "president",
+			"child_type":     "minister",
+			"rel_type":       "AS_MINISTER",
+			"transaction_id": transactionID,
+			"president":      presidentName,
+		}
+		newMinisterCounter, err = c.AddOrgEntity(addEntityTransaction, entityCounters)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create new minister '%s': %w", oldMinister, err)
+		}
+		entityCounters["minister"] = newMinisterCounter
+
+		newMinisterEntity, err := c.GetActiveMinisterByPresident(presidentName, oldMinister, dateISO)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get new minister: %w", err)
+		}
+		newMinisterIDs[oldMinister] = newMinisterEntity.ID
+
+		// Departments mapped to it are still held by the old minister
+		for _, rel := range activeRelationships(oldRelations) {
+			department, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+			if err != nil || len(department) == 0 {
+				return 0, fmt.Errorf("failed to find department with ID: %s", rel.RelatedEntityID)
+			}
+			if departmentMapping[department[0].Name] != oldMinister {
+				continue
+			}
+			err = c.createRelationship(newMinisterEntity.ID, rel.RelatedEntityID, "AS_DEPARTMENT", dateISO)
+			if err != nil {
+				return 0, err
+			}
+			err = c.terminateRelationship(oldMinisterID, rel.ID, dateISO)
+			if err != nil {
+				return 0, err
+			}
+			err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		// Appointments kept with it were ended with the old minister and start again
+		if appointees == oldMinister {
+			for _, rel := range activePeopleRelations {
+				err = c.createRelationship(newMinisterEntity.ID, rel.RelatedEntityID, rel.Name, dateISO)
+				if err != nil {
+					return 0, err
+				}
+				err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+
+	// 5. Create old minister -> new minister SPLIT_INTO relationships
+	for _, newMinister := range newMinisters {
+		err = c.createRelationship(oldMinisterID, newMinisterIDs[newMinister], "SPLIT_INTO", dateISO)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return newMinisterCounter, nil
+}
+
+// AddPersonEntity creates a new person entity and establishes its relationship with a parent entity.
+// Assumes the parent entity already exists.
+func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
+	// Extract details from the transaction
+	parent := transaction["parent"].(string)
+	child := transaction["child"].(string)
+	dateStr := transaction["date"].(string)
+	parentType := transaction["parent_type"].(string)
+	childType := transaction["child_type"].(string)
+	transactionID := transaction["transaction_id"].(string)
+
+	// Get president name -> people appointed to ministers and departments are looked up under the president
+	presidentName, _ := transaction["president"].(string)
+
+	// Determine the role from the "role" or "rel_type" column and check it is allowed on the parent
+	role, err := resolveAppointmentRole(transaction, "role", parentType)
+	if err != nil {
+		return 0, err
+	}
+	relType := role.RelType
+
+	// Parse the date
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	// Get the parent entity ID
+	parentID, err := c.getAppointmentParentID(parent, parentType, presidentName, dateISO)
+	if err != nil {
+		return 0, err
+	}
+
+	// Check if person already exists, resolving name variants through the person registry
+	person, err := c.resolvePerson(child, transaction)
+	if err != nil {
+		return 0, err
+	}
+
+	// Attribute columns (party, electorate, ...) are recorded on the person from the transaction date
+	attributes := attributesFromTransaction(transaction, dateISO)
+
 	var childID string
-	if len(personResults) == 1 {
+	if person.Exists {
 		// Person exists, use existing ID
-		childID = personResults[0].ID
+		childID = person.ID
+		err = c.setEntityAttributes(childID, attributes)
+		if err != nil {
+			return 0, err
+		}
 	} else {
-		// Generate new entity ID
-		if _, exists := entityCounters[childType]; !exists {
-			return 0, fmt.Errorf("unknown child type: %s", childType)
+		// Point out people with similar names, they may be the same person under another spelling
+		if suggestions := c.suggestPeople(child); len(suggestions) > 0 {
+			fmt.Printf("Warning: creating new person '%s', similar names already known: %s\n", child, strings.Join(suggestions, ", "))
 		}
 
-		// Get the part before the first underscore for the prefix
-		prefixPart := strings.Split(transactionID, "_")[0]
-		prefix := fmt.Sprintf("%s_%s", prefixPart, strings.ToLower(childType[:3]))
-		entityCounters[childType]++ // Increment the counter
-		newEntityID := fmt.Sprintf("%s_%d", prefix, entityCounters[childType])
+		// Use the canonical person ID when known, otherwise generate a new entity ID
+		newEntityID := person.ID
+		if newEntityID == "" {
+			if _, exists := entityCounters[childType]; !exists {
+				return 0, fmt.Errorf("unknown child type: %s", childType)
+			}
+
+			// Get the part before the first underscore for the prefix
+			prefixPart := strings.Split(transactionID, "_")[0]
+			prefix := fmt.Sprintf("%s_%s", prefixPart, strings.ToLower(childType[:3]))
+			entityCounters[childType]++ // Increment the counter
+			newEntityID = fmt.Sprintf("%s_%d", prefix, entityCounters[childType])
+		}
 
 		// Create the new child entity
 		childEntity := &models.Entity{
@@ -1549,10 +1967,10 @@ func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCount
 			Terminated: "",
 			Name: models.TimeBasedValue{
 				StartTime: dateISO,
-				Value:     child,
+				Value:     person.Name,
 			},
-			Metadata:      []models.MetadataEntry{},
-			Attributes:    []models.AttributeEntry{},
+			Metadata:      attributeNameMetadata(attributes),
+			Attributes:    attributes,
 			Relationships: []models.RelationshipEntry{},
 		}
 
@@ -1562,6 +1980,7 @@ func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCount
 			return 0, fmt.Errorf("failed to create child entity: %w", err)
 		}
 		childID = createdChild.ID
+		c.rememberPerson(person.Name)
 	}
 
 	// Update the parent entity to add the relationship to the child
@@ -1596,6 +2015,12 @@ func (c *Client) AddPersonEntity(transaction map[string]interface{}, entityCount
 		return 0, fmt.Errorf("failed to update parent entity: %w", err)
 	}
 
+	// Link the person to the gazette the appointment was taken from
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return 0, err
+	}
+
 	return entityCounters[childType], nil
 }
 
@@ -1606,16 +2031,21 @@ func (c *Client) TerminatePersonEntity(transaction map[string]interface{}) error
 	child := transaction["child"].(string)
 	dateStr := transaction["date"].(string)
 	parentType := transaction["parent_type"].(string)
-	childType := transaction["child_type"].(string)
-	relType := transaction["rel_type"].(string)
 
-	// Get president name if parent is a minister -> currently only supports terminating relationships with ministers
+	// Determine the role from the "role" or "rel_type" column and check it is allowed on the parent
+	role, err := resolveAppointmentRole(transaction, "role", parentType)
+	if err != nil {
+		return err
+	}
+	relType := role.RelType
+
+	// Get president name if parent is a minister or department -> these are looked up under the president
 	var presidentName string
-	if parentType == "minister" {
+	if parentType == "minister" || IsInstitutionType(parentType) {
 		var ok bool
 		presidentName, ok = transaction["president"].(string)
 		if !ok || presidentName == "" {
-			return fmt.Errorf("president name is required and must be a non-empty string when terminating relationships with ministers")
+			return fmt.Errorf("president name is required and must be a non-empty string when terminating relationships with ministers or institutions")
 		}
 	}
 
@@ -1627,22 +2057,10 @@ func (c *Client) TerminatePersonEntity(transaction map[string]interface{}) error
 	dateISO := date.Format(time.RFC3339)
 
 	// First, find the person (child) entity
-	childSearchCriteria := &models.SearchCriteria{
-		Kind: &models.Kind{
-			Major: "Person",
-			Minor: childType,
-		},
-		Name: child,
-	}
-
-	childResults, err := c.SearchEntities(childSearchCriteria)
+	childID, err := c.getPersonID(child, transaction)
 	if err != nil {
-		return fmt.Errorf("failed to search for child entity: %w", err)
-	}
-	if len(childResults) == 0 {
-		return fmt.Errorf("child entity not found: %s", child)
+		return err
 	}
-	childID := childResults[0].ID
 
 	// Find the ministry by checking the person's active relationships
 	var parentID string
@@ -1701,6 +2119,13 @@ func (c *Client) TerminatePersonEntity(transaction map[string]interface{}) error
 		if parentID == "" {
 			return fmt.Errorf("no active relationship found between person '%s' (ID: %s) and ministry '%s' under president '%s'", child, childID, parent, presidentName)
 		}
+	} else if IsInstitutionType(parentType) {
+		// Parent is a department or other institution, need president context to get the correct one
+		institutionEntity, err := c.getActiveInstitutionByPresident(presidentName, parent, parentType, dateISO)
+		if err != nil {
+			return fmt.Errorf("failed to get parent %s entity: %w", parentType, err)
+		}
+		parentID = institutionEntity.ID
 	} else {
 		// For other parent types, use the original logic
 		searchCriteria := &models.SearchCriteria{
@@ -1767,26 +2192,62 @@ func (c *Client) TerminatePersonEntity(transaction map[string]interface{}) error
 		return fmt.Errorf("failed to terminate relationship: %w", err)
 	}
 
+	// Link the person to the gazette the termination was taken from
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// MovePerson moves a person from one portfolio to another (limits functionality to only minister)
-// TODO: Take the parent type from the transaction such that this function can be used generic
-//
-//	for moving person from any institution to another
+// MovePerson moves a person from one portfolio to another.
+// The parents default to ministers and can be any entity kind through the "old_parent_type" and
+// "new_parent_type" columns. The "role" column gives the role held on the new parent (defaults to the
+// role given by "rel_type", or cabinet minister) and "old_role" the role being ended (defaults to "role").
 func (c *Client) MovePerson(transaction map[string]interface{}) error {
 	// Extract details from the transaction
 	newParent := transaction["new_parent"].(string)
 	oldParent := transaction["old_parent"].(string)
 	child := transaction["child"].(string)
 	dateStr := transaction["date"].(string)
-	relType := "AS_APPOINTED"
 
-	// Validate president name is provided
+	oldParentType := "minister"
+	if value, ok := transaction["old_parent_type"].(string); ok && strings.TrimSpace(value) != "" {
+		oldParentType = strings.TrimSpace(value)
+	}
+	newParentType := "minister"
+	if value, ok := transaction["new_parent_type"].(string); ok && strings.TrimSpace(value) != "" {
+		newParentType = strings.TrimSpace(value)
+	}
+
+	// Validate president name is provided, the old and new parents can be under different presidents
 	presidentName, ok := transaction["president"].(string)
 	if !ok || presidentName == "" {
 		return fmt.Errorf("president name is required and must be a non-empty string")
 	}
+	oldPresidentName := presidentName
+	if value, ok := transaction["old_president_name"].(string); ok && strings.TrimSpace(value) != "" {
+		oldPresidentName = strings.TrimSpace(value)
+	}
+	newPresidentName := presidentName
+	if value, ok := transaction["new_president_name"].(string); ok && strings.TrimSpace(value) != "" {
+		newPresidentName = strings.TrimSpace(value)
+	}
+
+	// Determine the new role and the role being ended
+	newRole, err := resolveAppointmentRole(transaction, "role", newParentType)
+	if err != nil {
+		return fmt.Errorf("invalid role for new parent: %w", err)
+	}
+	oldRoleKey := "role"
+	if value, ok := transaction["old_role"].(string); ok && strings.TrimSpace(value) != "" {
+		oldRoleKey = "old_role"
+	}
+	oldRole, err := resolveAppointmentRole(transaction, oldRoleKey, oldParentType)
+	if err != nil {
+		return fmt.Errorf("invalid role for old parent: %w", err)
+	}
 
 	// Parse the date
 	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
@@ -1795,51 +2256,20 @@ func (c *Client) MovePerson(transaction map[string]interface{}) error {
 	}
 	dateISO := date.Format(time.RFC3339)
 
-	// Get the new minister (parent) entity ID -> only supports moving person to and from minister
-	newParentEntity, err := c.GetActiveMinisterByPresident(presidentName, newParent, dateISO)
+	// Get the new parent entity ID
+	newParentID, err := c.getAppointmentParentID(newParent, newParentType, newPresidentName, dateISO)
 	if err != nil {
 		return fmt.Errorf("failed to get new parent entity: %w", err)
 	}
-	newParentID := newParentEntity.ID
 
-	// Get the department (child) entity ID
-	childResults, err := c.SearchEntities(&models.SearchCriteria{
-		Kind: &models.Kind{
-			Major: "Person",
-			Minor: "citizen",
-		},
-		Name: child,
-	})
+	// Get the person (child) entity ID
+	childID, err := c.getPersonID(child, transaction)
 	if err != nil {
-		return fmt.Errorf("failed to search for child entity: %w", err)
+		return err
 	}
-	if len(childResults) == 0 {
-		return fmt.Errorf("child entity not found: %s", child)
-	}
-	childID := childResults[0].ID
-
-	// Create new relationship between new minister and person
-	// Use transaction ID and current timestamp to ensure unique relationship ID
-	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
-	uniqueRelationshipID := fmt.Sprintf("%s_%s_%s", newParentID, childID, currentTimestamp)
 
-	newRelationship := &models.Entity{
-		ID: newParentID,
-		Relationships: []models.RelationshipEntry{
-			{
-				Key: uniqueRelationshipID,
-				Value: models.Relationship{
-					RelatedEntityID: childID,
-					StartTime:       dateISO,
-					EndTime:         "",
-					ID:              uniqueRelationshipID,
-					Name:            relType,
-				},
-			},
-		},
-	}
-
-	_, err = c.UpdateEntity(newParentID, newRelationship)
+	// Create new relationship between new parent and person
+	err = c.createRelationship(newParentID, childID, newRole.RelType, dateISO)
 	if err != nil {
 		return fmt.Errorf("failed to create new relationship: %w", err)
 	}
@@ -1849,10 +2279,11 @@ func (c *Client) MovePerson(transaction map[string]interface{}) error {
 		"parent":      oldParent,
 		"child":       child,
 		"date":        dateStr,
-		"parent_type": "minister",
+		"parent_type": oldParentType,
 		"child_type":  "citizen",
-		"rel_type":    relType,
-		"president":   presidentName,
+		"rel_type":    oldRole.RelType,
+		"president":   oldPresidentName,
+		"person_id":   childID,
 	}
 
 	err = c.TerminatePersonEntity(terminateTransaction)
@@ -1860,6 +2291,12 @@ func (c *Client) MovePerson(transaction map[string]interface{}) error {
 		return fmt.Errorf("failed to terminate old relationship: %w", err)
 	}
 
+	// Link the person to the gazette the move was taken from
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1932,7 +2369,17 @@ func (c *Client) MovePerson(transaction map[string]interface{}) error {
 // 	return nil
 // }
 
-// MoveMinister moves a minister from one president to another
+// Appointee options for MoveMinister, given in the "appointees" column of a minister MOVE row
+const (
+	AppointeesKeep      = "keep"      // appointments stay on the minister (default)
+	AppointeesTerminate = "terminate" // active appointments are ended on the move date
+	AppointeesReassign  = "reassign"  // active appointments are ended and the new president is appointed instead
+)
+
+// MoveMinister moves a minister from one president to another.
+// The optional "appointees" column decides what happens to the people appointed to the minister
+// (keep, terminate or reassign to the new president). Departments stay attached to the minister, which
+// fails when one of them is also held by another minister.
 func (c *Client) MoveMinister(transaction map[string]interface{}) error {
 	// Extract details from the transaction
 	newParent := transaction["new_parent"].(string)
@@ -1940,6 +2387,14 @@ func (c *Client) MoveMinister(transaction map[string]interface{}) error {
 	child := transaction["child"].(string)
 	dateStr := transaction["date"].(string)
 
+	appointees := AppointeesKeep
+	if value, ok := transaction["appointees"].(string); ok && strings.TrimSpace(value) != "" {
+		appointees = strings.ToLower(strings.TrimSpace(value))
+	}
+	if appointees != AppointeesKeep && appointees != AppointeesTerminate && appointees != AppointeesReassign {
+		return fmt.Errorf("invalid appointees option '%s', must be one of: %s, %s, %s", appointees, AppointeesKeep, AppointeesTerminate, AppointeesReassign)
+	}
+
 	// Parse the date
 	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
 	if err != nil {
@@ -1968,6 +2423,13 @@ func (c *Client) MoveMinister(transaction map[string]interface{}) error {
 	}
 	childID := ministerEntity.ID
 
+	// Departments follow the minister. Checked before anything is written so a failing move leaves the
+	// minister untouched.
+	_, err = c.getMovableDepartments(childID, child)
+	if err != nil {
+		return err
+	}
+
 	// Create new relationship between new president and minister
 	// Use transaction ID and current timestamp to ensure unique relationship ID
 	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
@@ -2032,17 +2494,88 @@ func (c *Client) MoveMinister(transaction map[string]interface{}) error {
 		}
 	}
 
+	// Cascade to the people appointed to the minister
+	if appointees != AppointeesKeep {
+		cabinetRelType := appointmentRoles["cabinet_minister"].RelType
+		activePeopleRelations, err := c.getActiveAppointments(childID, "minister")
+		if err != nil {
+			return fmt.Errorf("failed to get minister's people relationships: %w", err)
+		}
+
+		newPresidentAppointed := false
+		for _, rel := range activePeopleRelations {
+			if appointees == AppointeesReassign && rel.RelatedEntityID == newParentID && rel.Name == cabinetRelType {
+				// The new president already holds the portfolio, keep the existing appointment
+				newPresidentAppointed = true
+				continue
+			}
+			err = c.terminateRelationship(childID, rel.ID, dateISO)
+			if err != nil {
+				return fmt.Errorf("failed to terminate person relationship: %w", err)
+			}
+			err = c.recordSource(transaction, rel.RelatedEntityID, dateISO)
+			if err != nil {
+				return err
+			}
+		}
+
+		if appointees == AppointeesReassign && !newPresidentAppointed {
+			err = c.createRelationship(childID, newParentID, cabinetRelType, dateISO)
+			if err != nil {
+				return fmt.Errorf("failed to appoint new president to minister: %w", err)
+			}
+			err = c.recordSource(transaction, newParentID, dateISO)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Link the moved minister to the gazette the move was taken from
+	err = c.recordSource(transaction, childID, dateISO)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// Document Entity Handling
-// Unlike other entities, Documents are not terminated, but there is an aspect to a document being
-// regarded in various states. So this needs to be thoroughly thought and represented in the system.
-// For now we are only adding the documents and not maintaining any other states.
+// getMovableDepartments returns the active department relationships of a minister, which move with it, and
+// fails when one of the departments is also held by another minister
+func (c *Client) getMovableDepartments(ministerID, ministerName string) ([]models.Relationship, error) {
+	departmentRelations, err := c.GetRelatedEntities(ministerID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get minister's department relationships: %w", err)
+	}
+	activeDepartments := activeRelationships(departmentRelations)
+	for _, rel := range activeDepartments {
+		holderRelations, err := c.GetRelatedEntities(rel.RelatedEntityID, &models.Relationship{
+			Name:      "AS_DEPARTMENT",
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get department relationships: %w", err)
+		}
+		for _, holderRel := range activeRelationships(holderRelations) {
+			if holderRel.RelatedEntityID != ministerID {
+				return nil, fmt.Errorf("department '%s' of minister '%s' is also held by '%s'", rel.RelatedEntityID, ministerName, holderRel.RelatedEntityID)
+			}
+		}
+	}
+	return activeDepartments, nil
+}
 
-// AddDocumentEntity creates a new document entity and establishes its relationship with a parent entity.
-// The document type is determined by the parent entity type (Organization or Person).
-// Assumes the parent entity already exists.
+// Document Entity Handling
+// Unlike other entities, Documents are not terminated. Whether a document is still in force is given by the
+// documents amending, correcting or superseding it (see AddDocumentLifecycle in documents.go).
+
+// AddDocumentEntity creates a new document entity and establishes its relationship with its parent entities.
+// The parent column may list several parents ("[A; B]") of any kind: government, president, citizen,
+// minister or an institution type, resolved under the president of the transaction.
+// Ingesting the same document again refreshes its metadata and does not link it to a parent twice.
+// Assumes the parent entities already exist.
 func (c *Client) AddDocumentEntity(transaction map[string]interface{}, entityCounters map[string]int) (int, error) {
 	// Extract details from the transaction with validation
 	parent, ok := transaction["parent"].(string)
@@ -2082,26 +2615,12 @@ func (c *Client) AddDocumentEntity(transaction map[string]interface{}, entityCou
 	}
 	dateISO := date.Format(time.RFC3339)
 
-	// Get the parent entity ID (which is always gonna be an organisation)
-	searchCriteria := &models.SearchCriteria{
-		Name: parent,
-		Kind: &models.Kind{
-			Major: "Organisation",
-			Minor: parentType,
-		},
-	}
-
-	searchResults, err := c.SearchEntities(searchCriteria)
+	// Resolve the entities the document belongs to
+	parentIDs, err := c.resolveDocumentParents(transaction, parent, parentType, dateISO)
 	if err != nil {
-		return 0, fmt.Errorf("failed to search for parent entity: %w", err)
-	}
-
-	if len(searchResults) == 0 {
-		return 0, fmt.Errorf("parent entity not found: %s", parent)
+		return 0, err
 	}
 
-	parentID := searchResults[0].ID
-
 	// Check if document already exists
 	documentSearchCriteria := &models.SearchCriteria{
 		Kind: &models.Kind{
@@ -2120,11 +2639,25 @@ func (c *Client) AddDocumentEntity(transaction map[string]interface{}, entityCou
 		return 0, fmt.Errorf("multiple entities found for document: %s", child)
 	}
 
+	metadata, err := documentMetadata(transaction, child, dateISO)
+	if err != nil {
+		return 0, err
+	}
+
 	var childID string
-	entityCounter := 0
+	entityCounter := entityCounters["document"]
 	if len(documentResults) == 1 {
-		// Document exists, use existing ID
+		// Document exists, use existing ID and refresh its metadata
 		childID = documentResults[0].ID
+		if len(metadata) > 0 {
+			_, err = c.UpdateEntity(childID, &models.Entity{
+				ID:       childID,
+				Metadata: metadata,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to update document metadata: %w", err)
+			}
+		}
 	} else {
 		// Generate new entity ID
 		// Get the part before the first underscore for the prefix
@@ -2146,7 +2679,7 @@ func (c *Client) AddDocumentEntity(transaction map[string]interface{}, entityCou
 				StartTime: dateISO,
 				Value:     child,
 			},
-			Metadata:      []models.MetadataEntry{},
+			Metadata:      metadata,
 			Attributes:    []models.AttributeEntry{},
 			Relationships: []models.RelationshipEntry{},
 		}
@@ -2157,38 +2690,30 @@ func (c *Client) AddDocumentEntity(transaction map[string]interface{}, entityCou
 			return 0, fmt.Errorf("failed to create document entity: %w", err)
 		}
 		childID = createdDocument.ID
-	}
-
-	// Update the parent entity to add the relationship to the document
-	// Use transaction ID and current timestamp to ensure unique relationship ID
-	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
-	uniqueRelationshipID := fmt.Sprintf("%s_%s_%s", parentID, childID, currentTimestamp)
 
-	parentEntity := &models.Entity{
-		ID:         parentID,
-		Kind:       models.Kind{},
-		Created:    "",
-		Terminated: "",
-		Name:       models.TimeBasedValue{},
-		Metadata:   []models.MetadataEntry{},
-		Attributes: []models.AttributeEntry{},
-		Relationships: []models.RelationshipEntry{
-			{
-				Key: uniqueRelationshipID,
-				Value: models.Relationship{
-					RelatedEntityID: childID,
-					StartTime:       dateISO,
-					EndTime:         "",
-					ID:              uniqueRelationshipID,
-					Name:            "AS_DOCUMENT",
-				},
-			},
-		},
+		// The gazette may have been looked up as a source before it was loaded
+		delete(c.sourceDocuments, child)
 	}
 
-	_, err = c.UpdateEntity(parentID, parentEntity)
-	if err != nil {
-		return 0, fmt.Errorf("failed to update parent entity: %w", err)
+	// Link the document to each parent it is not linked to yet
+	for _, parentID := range parentIDs {
+		if len(documentResults) == 1 {
+			existing, err := c.GetRelatedEntities(parentID, &models.Relationship{
+				Name:            "AS_DOCUMENT",
+				RelatedEntityID: childID,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to get document relationships: %w", err)
+			}
+			if len(existing) > 0 {
+				continue
+			}
+		}
+
+		err = c.createRelationship(parentID, childID, "AS_DOCUMENT", dateISO)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update parent entity: %w", err)
+		}
 	}
 
 	return entityCounter, nil
@@ -21,9 +21,16 @@ func (c *Client) ProcessDocumentTransactions(dataDir string, processType string)
 		return fmt.Errorf("failed to read directory %s: %w", dataDir, err)
 	}
 
+	// Documents are added before the amendments, corrections and supersessions linking them
+	var lifecycleTransactions []map[string]interface{}
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".csv") && strings.HasSuffix(file.Name(), "_ADD.csv") {
-			transactions, err := loadTransactions(filepath.Join(dataDir, file.Name()), "ADD")
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".csv") {
+			continue
+		}
+		fileName := strings.TrimSuffix(file.Name(), ".csv")
+
+		if strings.HasSuffix(file.Name(), "_ADD.csv") {
+			transactions, err := loadTransactions(filepath.Join(dataDir, file.Name()), "ADD", c.presidents)
 			if err != nil {
 				return fmt.Errorf("failed to load transactions from %s: %w", file.Name(), err)
 			}
@@ -35,7 +42,33 @@ func (c *Client) ProcessDocumentTransactions(dataDir string, processType string)
 					}
 				}
 			}
+			continue
+		}
+
+		for fileType := range documentLifecycleRelTypes {
+			if strings.HasSuffix(fileName, "_"+fileType) {
+				transactions, err := loadTransactions(filepath.Join(dataDir, file.Name()), fileType, c.presidents)
+				if err != nil {
+					return fmt.Errorf("failed to load transactions from %s: %w", file.Name(), err)
+				}
+				lifecycleTransactions = append(lifecycleTransactions, transactions...)
+				break
+			}
+		}
+	}
+
+	// Apply the lifecycle changes in order of effect
+	sort.SliceStable(lifecycleTransactions, func(i, j int) bool {
+		dateI, _ := lifecycleTransactions[i]["date"].(string)
+		dateJ, _ := lifecycleTransactions[j]["date"].(string)
+		return dateI < dateJ
+	})
+	for _, transaction := range lifecycleTransactions {
+		err := c.AddDocumentLifecycle(transaction)
+		if err != nil {
+			return fmt.Errorf("failed to process %s transaction %s: %w", transaction["file_type"], transaction["transaction_id"], err)
 		}
+		fmt.Printf("Processed %s transaction: %s\n", transaction["file_type"], transaction["transaction_id"])
 	}
 
 	return nil
@@ -47,8 +80,10 @@ func (c *Client) ProcessTransactions(dataDir string, processType string) error {
 	var entityCounters map[string]int
 	if processType == "organisation" {
 		entityCounters = map[string]int{
-			"minister":   0,
-			"department": 0,
+			"minister": 0,
+		}
+		for _, institutionType := range InstitutionTypes {
+			entityCounters[institutionType] = 0
 		}
 	} else if processType == "person" {
 		entityCounters = map[string]int{
@@ -79,10 +114,20 @@ func (c *Client) ProcessTransactions(dataDir string, processType string) error {
 				fileType = "MERGE"
 			} else if strings.Contains(fileName, "RENAME") {
 				fileType = "RENAME"
+			} else if strings.Contains(fileName, "SPLIT") {
+				fileType = "SPLIT"
+			} else if strings.Contains(fileName, "TRANSITION") {
+				fileType = "TRANSITION"
+			} else if strings.Contains(fileName, "REINSTATE") {
+				fileType = "REINSTATE"
+			} else if strings.Contains(fileName, "AMEND") {
+				fileType = "AMEND"
+			} else if strings.Contains(fileName, "ATTRIBUTE") {
+				fileType = "ATTRIBUTE"
 			}
 
 			// Load transactions from the CSV file
-			transactions, err := loadTransactions(filepath.Join(dataDir, file.Name()), fileType)
+			transactions, err := loadTransactions(filepath.Join(dataDir, file.Name()), fileType, c.presidents)
 			if err != nil {
 				return fmt.Errorf("failed to load transactions from %s: %w", file.Name(), err)
 			}
@@ -127,7 +172,7 @@ func (c *Client) ProcessTransactions(dataDir string, processType string) error {
 		case "ADD":
 			// Check if the transaction type matches the process type
 			childType := transaction["child_type"].(string)
-			if (processType == "organisation" && (childType == "minister" || childType == "department")) ||
+			if (processType == "organisation" && (childType == "minister" || IsInstitutionType(childType))) ||
 				(processType == "person" && childType == "citizen") {
 				var err error
 
@@ -166,7 +211,7 @@ func (c *Client) ProcessTransactions(dataDir string, processType string) error {
 			if processType == "organisation" {
 				// Check if we're moving a department or a minister
 				childType := transaction["type"].(string)
-				if childType == "department" {
+				if IsInstitutionType(childType) {
 					err := c.MoveDepartment(transaction)
 					if err != nil {
 						return fmt.Errorf("failed to process move department transaction %s: %w", transaction["transaction_id"], err)
@@ -191,34 +236,87 @@ func (c *Client) ProcessTransactions(dataDir string, processType string) error {
 
 		case "MERGE":
 			if processType == "organisation" {
-				newCounter, err := c.MergeMinisters(transaction, entityCounters)
+				if transaction["type"] == "department" {
+					newCounter, err := c.MergeDepartments(transaction, entityCounters)
+					if err != nil {
+						return fmt.Errorf("failed to process merge department transaction %s: %w", transaction["transaction_id"], err)
+					}
+					entityCounters["department"] = newCounter
+					fmt.Printf("Processed Merge Department transaction: %s\n", transaction["transaction_id"])
+				} else {
+					newCounter, err := c.MergeMinisters(transaction, entityCounters)
+					if err != nil {
+						return fmt.Errorf("failed to process merge transaction %s: %w", transaction["transaction_id"], err)
+					}
+					entityCounters["minister"] = newCounter
+					fmt.Printf("Processed Merge transaction: %s\n", transaction["transaction_id"])
+				}
+			}
+
+		case "SPLIT":
+			if processType == "organisation" {
+				newCounter, err := c.SplitMinister(transaction, entityCounters)
 				if err != nil {
-					return fmt.Errorf("failed to process merge transaction %s: %w", transaction["transaction_id"], err)
+					return fmt.Errorf("failed to process split transaction %s: %w", transaction["transaction_id"], err)
 				}
 				entityCounters["minister"] = newCounter
-				fmt.Printf("Processed Merge transaction: %s\n", transaction["transaction_id"])
+				fmt.Printf("Processed Split transaction: %s\n", transaction["transaction_id"])
+			}
+
+		case "TRANSITION":
+			if processType == "organisation" {
+				summary, err := c.TransitionPresidency(transaction, entityCounters)
+				if err != nil {
+					return fmt.Errorf("failed to process transition transaction %s: %w", transaction["transaction_id"], err)
+				}
+				fmt.Printf("Processed Transition transaction: %s\n", transaction["transaction_id"])
+				fmt.Printf("  %s -> %s on %s: %d ministers transferred (%d departments carried), %d ministers terminated, appointees: %s\n",
+					summary.OutgoingPresident, summary.IncomingPresident, summary.Date,
+					len(summary.MinistersTransferred), summary.DepartmentsCarried, len(summary.MinistersTerminated), summary.AppointeesPolicy)
 			}
 
 		case "RENAME":
 			if processType == "organisation" {
 				var newCounter int
 				var err error
-				if transaction["type"] == "minister" {
+				renameType, _ := transaction["type"].(string)
+				if renameType == "minister" {
 					newCounter, err = c.RenameMinister(transaction, entityCounters)
-				} else if transaction["type"] == "department" {
+				} else if IsInstitutionType(renameType) {
 					newCounter, err = c.RenameDepartment(transaction, entityCounters)
 				}
 				if err != nil {
 					return fmt.Errorf("failed to process rename transaction %s: %w", transaction["transaction_id"], err)
 				}
-				if transaction["type"] == "minister" {
-					entityCounters["minister"] = newCounter
-				} else if transaction["type"] == "department" {
-					entityCounters["department"] = newCounter
+				if renameType == "minister" || IsInstitutionType(renameType) {
+					entityCounters[renameType] = newCounter
 				}
 				fmt.Printf("Processed Rename transaction: %s\n", transaction["transaction_id"])
 			}
 
+		case "REINSTATE":
+			err := c.ReinstateRelationship(transaction)
+			if err != nil {
+				return fmt.Errorf("failed to process reinstate transaction %s: %w", transaction["transaction_id"], err)
+			}
+			fmt.Printf("Processed Reinstate transaction: %s\n", transaction["transaction_id"])
+
+		case "AMEND":
+			err := c.AmendRelationship(transaction)
+			if err != nil {
+				return fmt.Errorf("failed to process amend transaction %s: %w", transaction["transaction_id"], err)
+			}
+			fmt.Printf("Processed Amend transaction: %s\n", transaction["transaction_id"])
+
+		case "ATTRIBUTE":
+			if processType == "person" {
+				err := c.SetPersonAttribute(transaction)
+				if err != nil {
+					return fmt.Errorf("failed to process attribute transaction %s: %w", transaction["transaction_id"], err)
+				}
+				fmt.Printf("Processed Attribute transaction: %s\n", transaction["transaction_id"])
+			}
+
 		default:
 			fmt.Printf("Skipping unknown transaction type: %s\n", transaction["file_type"])
 		}
@@ -242,13 +340,20 @@ func extractPresidentNameFromPath(filePath string) (string, error) {
 	return "", fmt.Errorf("neither 'orgchart' nor 'people' nor 'documents' found in path: %s", filePath)
 }
 
-// loadTransactions reads and processes transactions from a CSV file
-func loadTransactions(filePath string, fileType string) ([]map[string]interface{}, error) {
+// loadTransactions reads and processes transactions from a CSV file.
+// President aliases (e.g. ":RW") are resolved to canonical names when aliases are given.
+func loadTransactions(filePath string, fileType string, aliases *PresidentAliases) ([]map[string]interface{}, error) {
 	// Extract president name from file path
 	presidentName, err := extractPresidentNameFromPath(filePath)
 	if err != nil {
 		return nil, err
 	}
+	if aliases != nil {
+		presidentName, err = aliases.Resolve(presidentName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid president directory in %s: %w", filePath, err)
+		}
+	}
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -286,6 +391,12 @@ func loadTransactions(filePath string, fileType string) ([]map[string]interface{
 			transaction["president"] = presidentName
 		}
 
+		if aliases != nil {
+			if err := aliases.resolvePresidentColumns(transaction); err != nil {
+				return nil, fmt.Errorf("invalid president in transaction %s of %s: %w", transaction["transaction_id"], filePath, err)
+			}
+		}
+
 		transaction["file_type"] = fileType
 		transactions = append(transactions, transaction)
 	}
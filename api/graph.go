@@ -0,0 +1,281 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Graph export and import
+// The whole entity graph can be read through the Query API and written to portable files, then re-loaded
+// into an empty backend through the Update API, so a dataset can be shared without database dumps. The graph
+// is walked from the government along every relationship the tool creates, so entities not linked to the
+// government are not exported. Entities keep their IDs, metadata and attribute values (the person attributes
+// and the classification of ministers); relationships keep their IDs and periods.
+
+// graphRelTypes returns the relationships followed when reading the graph, in a stable order
+func graphRelTypes() []string {
+	relTypes := []string{"AS_PRESIDENT", "AS_MINISTER", "AS_DEPARTMENT", "AS_UNIT", "AS_DOCUMENT", SourcedFromRelType,
+		AmendsRelType, CorrectsRelType, SupersedesRelType}
+	relTypes = append(relTypes, lineageRelTypes...)
+
+	seen := make(map[string]bool)
+	for _, relType := range relTypes {
+		seen[relType] = true
+	}
+	for _, name := range appointmentRoleNames() {
+		if relType := appointmentRoles[name].RelType; !seen[relType] {
+			seen[relType] = true
+			relTypes = append(relTypes, relType)
+		}
+	}
+	return relTypes
+}
+
+// GetGraph reads every entity and relationship reachable from the government
+func (c *Client) GetGraph() (*models.Graph, error) {
+	governmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for government entity: %w", err)
+	}
+	if len(governmentResults) == 0 {
+		return nil, fmt.Errorf("government entity not found")
+	}
+
+	graph := &models.Graph{ExportedAt: time.Now().UTC().Format(time.RFC3339)}
+	visited := map[string]bool{governmentResults[0].ID: true}
+	queue := []models.SearchResult{governmentResults[0]}
+	relationshipIDs := make(map[string]bool)
+
+	for len(queue) > 0 {
+		entity := queue[0]
+		queue = queue[1:]
+
+		graphEntity, err := c.getGraphEntity(entity)
+		if err != nil {
+			return nil, err
+		}
+		graph.Entities = append(graph.Entities, graphEntity)
+
+		for _, relType := range graphRelTypes() {
+			relations, err := c.GetRelatedEntities(entity.ID, &models.Relationship{
+				Name:      relType,
+				Direction: "OUTGOING",
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, entity.ID, err)
+			}
+
+			for _, rel := range relations {
+				// Relationships are imported by ID, so one without an ID could not be loaded back
+				if rel.ID == "" {
+					return nil, fmt.Errorf("%s relationship from %s to %s has no ID", relType, entity.ID, rel.RelatedEntityID)
+				}
+				if relationshipIDs[rel.ID] {
+					continue
+				}
+				relationshipIDs[rel.ID] = true
+				graph.Relationships = append(graph.Relationships, models.GraphRelationship{
+					ID:        rel.ID,
+					Name:      relType,
+					From:      entity.ID,
+					To:        rel.RelatedEntityID,
+					StartTime: rel.StartTime,
+					EndTime:   rel.EndTime,
+				})
+
+				if visited[rel.RelatedEntityID] {
+					continue
+				}
+				visited[rel.RelatedEntityID] = true
+				results, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+				if err != nil {
+					return nil, fmt.Errorf("failed to search for entity %s: %w", rel.RelatedEntityID, err)
+				}
+				if len(results) == 0 {
+					return nil, fmt.Errorf("entity not found: %s", rel.RelatedEntityID)
+				}
+				queue = append(queue, results[0])
+			}
+		}
+	}
+
+	sort.SliceStable(graph.Entities, func(i, j int) bool {
+		return graph.Entities[i].ID < graph.Entities[j].ID
+	})
+	sort.SliceStable(graph.Relationships, func(i, j int) bool {
+		return graph.Relationships[i].ID < graph.Relationships[j].ID
+	})
+	return graph, nil
+}
+
+// getGraphEntity reads the metadata and the attributes of an entity
+func (c *Client) getGraphEntity(entity models.SearchResult) (models.GraphEntity, error) {
+	graphEntity := models.GraphEntity{
+		ID:         entity.ID,
+		Kind:       entity.Kind,
+		Name:       entity.Name,
+		Created:    entity.Created,
+		Terminated: entity.Terminated,
+	}
+
+	metadata, err := c.GetEntityMetadata(entity.ID)
+	if err != nil {
+		return models.GraphEntity{}, fmt.Errorf("failed to get metadata of %s: %w", entity.ID, err)
+	}
+	if len(metadata) > 0 {
+		graphEntity.Metadata = metadata
+	}
+
+	for _, name := range graphAttributeNames(entity.Kind, metadata) {
+		result, err := c.GetEntityAttribute(entity.ID, name, "", "")
+		if err != nil {
+			return models.GraphEntity{}, fmt.Errorf("failed to get attribute %s of %s: %w", name, entity.ID, err)
+		}
+		if values := parseTimeBasedValues(result); len(values) > 0 {
+			if graphEntity.Attributes == nil {
+				graphEntity.Attributes = make(map[string][]models.TimeBasedValue)
+			}
+			graphEntity.Attributes[name] = values
+		}
+	}
+
+	return graphEntity, nil
+}
+
+// graphAttributeNames returns the attributes exported for an entity: the person attributes, including the
+// additional ones recorded in the metadata, and the classification of ministers
+func graphAttributeNames(kind models.Kind, metadata map[string]interface{}) []string {
+	switch {
+	case kind.Major == "Person":
+		return recordedAttributeNames(metadata)
+	case kind.Minor == "minister":
+		return []string{MinisterCategoryAttribute, MinisterGazettePositionAttribute}
+	default:
+		return nil
+	}
+}
+
+// ValidateGraph checks that every relationship of a graph links entities of the graph and that IDs are unique
+func ValidateGraph(graph *models.Graph) error {
+	entityIDs := make(map[string]bool)
+	for _, entity := range graph.Entities {
+		if entity.ID == "" {
+			return fmt.Errorf("entity '%s' has no ID", entity.Name)
+		}
+		if entityIDs[entity.ID] {
+			return fmt.Errorf("entity %s is listed more than once", entity.ID)
+		}
+		entityIDs[entity.ID] = true
+	}
+
+	relationshipIDs := make(map[string]bool)
+	for _, rel := range graph.Relationships {
+		if rel.ID == "" {
+			return fmt.Errorf("%s relationship from %s to %s has no ID", rel.Name, rel.From, rel.To)
+		}
+		if relationshipIDs[rel.ID] {
+			return fmt.Errorf("relationship %s is listed more than once", rel.ID)
+		}
+		relationshipIDs[rel.ID] = true
+		if !entityIDs[rel.From] || !entityIDs[rel.To] {
+			return fmt.Errorf("relationship %s links %s to %s, which are not both in the graph", rel.ID, rel.From, rel.To)
+		}
+	}
+	return nil
+}
+
+// ImportGraph loads a graph into an empty backend. Entities are created first, then each relationship is
+// added to the entity owning it. The backend must not have a government yet.
+func (c *Client) ImportGraph(graph *models.Graph) error {
+	if err := ValidateGraph(graph); err != nil {
+		return err
+	}
+
+	governmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search for government entity: %w", err)
+	}
+	if len(governmentResults) > 0 {
+		return fmt.Errorf("the backend is not empty (government %s exists), import only into an empty backend", governmentResults[0].ID)
+	}
+
+	for _, entity := range graph.Entities {
+		if _, err := c.CreateEntity(graphEntityToEntity(entity)); err != nil {
+			return fmt.Errorf("failed to create entity %s: %w", entity.ID, err)
+		}
+	}
+
+	for _, rel := range graph.Relationships {
+		_, err := c.UpdateEntity(rel.From, &models.Entity{
+			ID: rel.From,
+			Relationships: []models.RelationshipEntry{
+				{
+					Key: rel.ID,
+					Value: models.Relationship{
+						RelatedEntityID: rel.To,
+						StartTime:       rel.StartTime,
+						EndTime:         rel.EndTime,
+						ID:              rel.ID,
+						Name:            rel.Name,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create %s relationship %s from %s to %s: %w", rel.Name, rel.ID, rel.From, rel.To, err)
+		}
+	}
+
+	fmt.Printf("Imported %d entities and %d relationships\n", len(graph.Entities), len(graph.Relationships))
+	return nil
+}
+
+// graphEntityToEntity builds the Update API entity of an exported entity, with metadata in a stable order
+func graphEntityToEntity(entity models.GraphEntity) *models.Entity {
+	created := &models.Entity{
+		ID:         entity.ID,
+		Kind:       entity.Kind,
+		Created:    entity.Created,
+		Terminated: entity.Terminated,
+		Name: models.TimeBasedValue{
+			StartTime: entity.Created,
+			Value:     entity.Name,
+		},
+	}
+
+	var keys []string
+	for key := range entity.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		created.Metadata = append(created.Metadata, models.MetadataEntry{Key: key, Value: entity.Metadata[key]})
+	}
+
+	var names []string
+	for name := range entity.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		created.Attributes = append(created.Attributes, models.AttributeEntry{
+			Key:   name,
+			Value: models.AttributeValueCollection{Values: entity.Attributes[name]},
+		})
+	}
+
+	return created
+}
@@ -0,0 +1,409 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"orgchart_nexoan/models"
+)
+
+// Person identity resolution
+// People are referred to by name in the transaction files, but the same person appears under different
+// spellings ("D.B. Herath", "Don Bandara Herath") and different people can share a name. The registry maps
+// canonical person IDs to the known name variants. A "person_id" column in a people CSV pins the person
+// explicitly; otherwise the name is resolved through the registry and finally by exact name match.
+
+// PersonRegistry holds the known identities of people and their name variants
+type PersonRegistry struct {
+	identities map[string]models.PersonIdentity // by person ID
+	byName     map[string][]string              // normalized name -> person IDs
+}
+
+// NewPersonRegistry creates a registry from the given identities
+func NewPersonRegistry(identities []models.PersonIdentity) (*PersonRegistry, error) {
+	registry := &PersonRegistry{
+		identities: make(map[string]models.PersonIdentity),
+		byName:     make(map[string][]string),
+	}
+
+	for _, identity := range identities {
+		identity.PersonID = strings.TrimSpace(identity.PersonID)
+		identity.Name = strings.TrimSpace(identity.Name)
+		if identity.PersonID == "" {
+			return nil, fmt.Errorf("person identity '%s' has no person_id", identity.Name)
+		}
+		if identity.Name == "" {
+			return nil, fmt.Errorf("person identity '%s' has no name", identity.PersonID)
+		}
+		if _, exists := registry.identities[identity.PersonID]; exists {
+			return nil, fmt.Errorf("duplicate person_id '%s' in person registry", identity.PersonID)
+		}
+		registry.identities[identity.PersonID] = identity
+
+		seen := make(map[string]bool)
+		for _, name := range append([]string{identity.Name}, identity.Aliases...) {
+			key := normalizePersonName(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			registry.byName[key] = append(registry.byName[key], identity.PersonID)
+		}
+	}
+
+	return registry, nil
+}
+
+// LoadPersonRegistry reads a person registry from a JSON or CSV file.
+// JSON files hold a list of {"person_id", "name", "aliases"} objects. CSV files have the columns
+// person_id, name and aliases, where aliases is a semicolon separated list such as "[D.B. Herath;Herath D.B.]".
+func LoadPersonRegistry(path string) (*PersonRegistry, error) {
+	var identities []models.PersonIdentity
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read person registry: %w", err)
+		}
+		if err := json.Unmarshal(data, &identities); err != nil {
+			return nil, fmt.Errorf("failed to parse person registry: %w", err)
+		}
+
+	case ".csv":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open person registry: %w", err)
+		}
+		defer file.Close()
+
+		records, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read person registry: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("person registry %s is empty", path)
+		}
+
+		columns := make(map[string]int)
+		for i, header := range records[0] {
+			columns[strings.TrimSpace(header)] = i
+		}
+		for _, required := range []string{"person_id", "name"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("person registry %s is missing the %s column", path, required)
+			}
+		}
+
+		for _, record := range records[1:] {
+			identity := models.PersonIdentity{
+				PersonID: record[columns["person_id"]],
+				Name:     record[columns["name"]],
+			}
+			if i, ok := columns["aliases"]; ok && i < len(record) {
+				identity.Aliases = parseNameList(record[i])
+			}
+			identities = append(identities, identity)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported person registry format '%s', must be .json or .csv", filepath.Ext(path))
+	}
+
+	return NewPersonRegistry(identities)
+}
+
+// Get returns the identity with the given person ID
+func (r *PersonRegistry) Get(personID string) (models.PersonIdentity, bool) {
+	identity, ok := r.identities[personID]
+	return identity, ok
+}
+
+// Lookup returns the identities known by the given name or one of its variants
+func (r *PersonRegistry) Lookup(name string) []models.PersonIdentity {
+	var matches []models.PersonIdentity
+	for _, personID := range r.byName[normalizePersonName(name)] {
+		matches = append(matches, r.identities[personID])
+	}
+	return matches
+}
+
+// KnownAs reports whether the given name is the canonical name or an alias of the identity
+func (r *PersonRegistry) KnownAs(personID, name string) bool {
+	for _, id := range r.byName[normalizePersonName(name)] {
+		if id == personID {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns every name and alias in the registry
+func (r *PersonRegistry) Names() []string {
+	var names []string
+	for _, identity := range r.identities {
+		names = append(names, identity.Name)
+		names = append(names, identity.Aliases...)
+	}
+	return names
+}
+
+// SetPersonRegistry sets the registry used to resolve people in person transactions
+func (c *Client) SetPersonRegistry(registry *PersonRegistry) {
+	c.people = registry
+}
+
+// resolvedPerson is the result of resolving the person a transaction refers to
+type resolvedPerson struct {
+	ID     string // entity ID, empty when a new person has to be created with a generated ID
+	Name   string // name to create the entity with
+	Exists bool   // whether the entity already exists
+}
+
+// resolvePerson determines the person a transaction refers to, using the "person_id" column, the person
+// registry and finally an exact name match, in that order. Ambiguous names are an error.
+func (c *Client) resolvePerson(name string, transaction map[string]interface{}) (resolvedPerson, error) {
+	personID, _ := transaction["person_id"].(string)
+	personID = strings.TrimSpace(personID)
+
+	// Resolve the name to a canonical identity through the registry
+	if personID == "" && c.people != nil {
+		matches := c.people.Lookup(name)
+		if len(matches) > 1 {
+			var ids []string
+			for _, match := range matches {
+				ids = append(ids, match.PersonID)
+			}
+			return resolvedPerson{}, fmt.Errorf("person name '%s' is ambiguous, it is known for person IDs %s; add a person_id column to choose one", name, strings.Join(ids, ", "))
+		}
+		if len(matches) == 1 {
+			personID = matches[0].PersonID
+		}
+	}
+
+	if personID != "" {
+		return c.resolvePersonByID(personID, name)
+	}
+
+	// Fall back to an exact name match
+	personResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Person",
+		},
+		Name: name,
+	})
+	if err != nil {
+		return resolvedPerson{}, fmt.Errorf("failed to search for person entity: %w", err)
+	}
+	if len(personResults) > 1 {
+		var ids []string
+		for _, result := range personResults {
+			ids = append(ids, result.ID)
+		}
+		return resolvedPerson{}, fmt.Errorf("person name '%s' is ambiguous, it matches entities %s; add a person_id column to choose one", name, strings.Join(ids, ", "))
+	}
+	if len(personResults) == 1 {
+		return resolvedPerson{ID: personResults[0].ID, Name: personResults[0].Name, Exists: true}, nil
+	}
+
+	return resolvedPerson{Name: name}, nil
+}
+
+// resolvePersonByID looks up the person entity with a canonical person ID. A person created before the
+// registry existed is found by its known names instead.
+func (c *Client) resolvePersonByID(personID, name string) (resolvedPerson, error) {
+	canonicalName := name
+	var knownNames []string
+	if c.people != nil {
+		if identity, ok := c.people.Get(personID); ok {
+			if !c.people.KnownAs(personID, name) {
+				fmt.Printf("Warning: '%s' is not a known name of person %s (%s)\n", name, personID, identity.Name)
+			}
+			canonicalName = identity.Name
+			knownNames = append([]string{identity.Name}, identity.Aliases...)
+		}
+	}
+
+	results, err := c.SearchEntities(&models.SearchCriteria{ID: personID})
+	if err != nil {
+		return resolvedPerson{}, fmt.Errorf("failed to search for person entity: %w", err)
+	}
+	if len(results) > 0 {
+		return resolvedPerson{ID: results[0].ID, Name: results[0].Name, Exists: true}, nil
+	}
+
+	var existing []models.SearchResult
+	for _, knownName := range knownNames {
+		nameResults, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Person",
+			},
+			Name: knownName,
+		})
+		if err != nil {
+			return resolvedPerson{}, fmt.Errorf("failed to search for person entity: %w", err)
+		}
+		existing = append(existing, nameResults...)
+	}
+	if len(existing) > 1 {
+		return resolvedPerson{}, fmt.Errorf("person %s matches several existing entities by name, they have to be merged first", personID)
+	}
+	if len(existing) == 1 {
+		return resolvedPerson{ID: existing[0].ID, Name: existing[0].Name, Exists: true}, nil
+	}
+
+	return resolvedPerson{ID: personID, Name: canonicalName}, nil
+}
+
+// getPersonID resolves a person that must already exist
+func (c *Client) getPersonID(name string, transaction map[string]interface{}) (string, error) {
+	person, err := c.resolvePerson(name, transaction)
+	if err != nil {
+		return "", err
+	}
+	if !person.Exists {
+		return "", fmt.Errorf("child entity %w: %s", ErrNotFound, name)
+	}
+	return person.ID, nil
+}
+
+// suggestPeople returns the known names that are similar to the given name, most similar first.
+// Candidates come from the person registry and the people already in the database, which are searched once
+// per client and kept up to date as people are created.
+func (c *Client) suggestPeople(name string) []string {
+	var candidates []string
+	if c.people != nil {
+		candidates = append(candidates, c.people.Names()...)
+	}
+	if c.knownPeople == nil {
+		personResults, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Person",
+			},
+		})
+		if err == nil {
+			c.knownPeople = make(map[string]bool)
+			for _, result := range personResults {
+				c.knownPeople[result.Name] = true
+			}
+		}
+	}
+	for knownName := range c.knownPeople {
+		candidates = append(candidates, knownName)
+	}
+
+	type scoredName struct {
+		name  string
+		score float64
+	}
+	var scored []scoredName
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] || normalizePersonName(candidate) == normalizePersonName(name) {
+			continue
+		}
+		seen[candidate] = true
+		if score := personNameSimilarity(name, candidate); score >= 0.8 {
+			scored = append(scored, scoredName{candidate, score})
+		}
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+		return scored[i].name < scored[j].name
+	})
+
+	var suggestions []string
+	for _, s := range scored {
+		suggestions = append(suggestions, s.name)
+	}
+	return suggestions
+}
+
+// rememberPerson adds a created person to the names used for suggestions, once they are loaded
+func (c *Client) rememberPerson(name string) {
+	if c.knownPeople != nil {
+		c.knownPeople[name] = true
+	}
+}
+
+// normalizePersonName lowercases a name and reduces punctuation and repeated spaces to single spaces,
+// so "D.B.  Herath" and "d b herath" compare equal
+func normalizePersonName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// personNameSimilarity scores how likely two names refer to the same person, between 0 and 1.
+// Names sharing a surname whose other parts are consistent as initials ("D.B. Herath", "Don Bandara Herath")
+// score 0.9; otherwise the score is based on the edit distance between the normalized names.
+func personNameSimilarity(a, b string) float64 {
+	tokensA := strings.Fields(normalizePersonName(a))
+	tokensB := strings.Fields(normalizePersonName(b))
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+
+	if tokensA[len(tokensA)-1] == tokensB[len(tokensB)-1] && initialsMatch(tokensA[:len(tokensA)-1], tokensB[:len(tokensB)-1]) {
+		return 0.9
+	}
+
+	normA := strings.Join(tokensA, " ")
+	normB := strings.Join(tokensB, " ")
+	longest := len([]rune(normA))
+	if l := len([]rune(normB)); l > longest {
+		longest = l
+	}
+	return 1 - float64(levenshtein(normA, normB))/float64(longest)
+}
+
+// initialsMatch reports whether two lists of given names agree on their initials, where a single letter
+// (or run of letters such as "db" from "D.B.") stands for the initials of the full names
+func initialsMatch(a, b []string) bool {
+	initials := func(tokens []string) string {
+		var result strings.Builder
+		for _, token := range tokens {
+			if len([]rune(token)) <= 2 {
+				result.WriteString(token)
+			} else {
+				result.WriteRune([]rune(token)[0])
+			}
+		}
+		return result.String()
+	}
+	initialsA := initials(a)
+	initialsB := initials(b)
+	return initialsA != "" && initialsA == initialsB
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	runesA := []rune(a)
+	runesB := []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
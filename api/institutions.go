@@ -0,0 +1,228 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Institutions
+// Gazette schedules list several kinds of institutions under a minister: departments, statutory boards,
+// public corporations and state-owned companies. They are all held by a minister through AS_DEPARTMENT and
+// can be nested below another institution through AS_UNIT. A nested unit is looked up under a president
+// through the minister holding its top-level institution.
+
+// Institution types (Kind.Minor)
+const (
+	InstitutionDepartment        = "department"
+	InstitutionStatutoryBoard    = "statutory_board"
+	InstitutionPublicCorporation = "public_corporation"
+	InstitutionStateOwnedCompany = "state_owned_company"
+)
+
+// InstitutionTypes lists the institution types that can be held by a minister
+var InstitutionTypes = []string{InstitutionDepartment, InstitutionStatutoryBoard, InstitutionPublicCorporation, InstitutionStateOwnedCompany}
+
+// maxUnitDepth bounds the walk up nested units, guarding against cycles in the data
+const maxUnitDepth = 10
+
+// IsInstitutionType reports whether the given entity kind is an institution type
+func IsInstitutionType(entityType string) bool {
+	for _, institutionType := range InstitutionTypes {
+		if entityType == institutionType {
+			return true
+		}
+	}
+	return false
+}
+
+// institutionRelType returns the relationship linking an institution to a parent of the given kind:
+// AS_DEPARTMENT from a minister and AS_UNIT from another institution
+func institutionRelType(parentType string) (string, error) {
+	if parentType == "minister" {
+		return "AS_DEPARTMENT", nil
+	}
+	if IsInstitutionType(parentType) {
+		return "AS_UNIT", nil
+	}
+	return "", fmt.Errorf("an institution must be attached to a minister or another institution, got parent_type: %s", parentType)
+}
+
+// entityIDCode returns the code used for the entity kind in generated entity IDs. Institution types whose
+// first three letters clash get their own code.
+func entityIDCode(entityType string) string {
+	switch entityType {
+	case InstitutionStatutoryBoard:
+		return "stb"
+	case InstitutionPublicCorporation:
+		return "pco"
+	case InstitutionStateOwnedCompany:
+		return "soc"
+	default:
+		return strings.ToLower(entityType[:3])
+	}
+}
+
+// institutionParent is the entity an institution is attached to under a president
+type institutionParent struct {
+	ID           string
+	Name         string
+	Type         string              // minister or an institution type
+	Relationship models.Relationship // the active relationship from the parent to the institution
+}
+
+// getInstitutionParent finds the active parent of an institution under the given president: the minister
+// holding it, or the institution it is a unit of
+func (c *Client) getInstitutionParent(presidentName, institutionID, dateISO string) (*institutionParent, error) {
+	minister, rel, err := c.getActiveMinisterOfDepartment(presidentName, institutionID, dateISO)
+	if err == nil {
+		return &institutionParent{
+			ID:           minister.ID,
+			Name:         minister.Name.Value.(string),
+			Type:         "minister",
+			Relationship: *rel,
+		}, nil
+	}
+
+	unitRelations, err := c.GetRelatedEntities(institutionID, &models.Relationship{
+		Name:      "AS_UNIT",
+		Direction: "INCOMING",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit relationships: %w", err)
+	}
+
+	for _, rel := range activeRelationships(unitRelations) {
+		parentResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+		if err != nil || len(parentResults) == 0 {
+			continue
+		}
+		parent := parentResults[0]
+
+		// The parent institution must itself be held under the president
+		if _, err := c.getOwningMinister(presidentName, parent.ID, dateISO); err == nil {
+			return &institutionParent{
+				ID:           parent.ID,
+				Name:         parent.Name,
+				Type:         parent.Kind.Minor,
+				Relationship: rel,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no active parent found for institution '%s' under president '%s'", institutionID, presidentName)
+}
+
+// getOwningMinister finds the minister under the given president that holds the institution, directly or
+// through the institutions it is nested in
+func (c *Client) getOwningMinister(presidentName, institutionID, dateISO string) (*models.Entity, error) {
+	currentID := institutionID
+	for depth := 0; depth < maxUnitDepth; depth++ {
+		minister, _, err := c.getActiveMinisterOfDepartment(presidentName, currentID, dateISO)
+		if err == nil {
+			return minister, nil
+		}
+
+		unitRelations, err := c.GetRelatedEntities(currentID, &models.Relationship{
+			Name:      "AS_UNIT",
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get unit relationships: %w", err)
+		}
+		activeUnitRelations := activeRelationships(unitRelations)
+		if len(activeUnitRelations) == 0 {
+			break
+		}
+		currentID = activeUnitRelations[0].RelatedEntityID
+	}
+
+	return nil, fmt.Errorf("no active minister found for institution '%s' under president '%s'", institutionID, presidentName)
+}
+
+// isNestedUnder reports whether the institution is the ancestor institution itself or one of its (nested) units
+func (c *Client) isNestedUnder(institutionID, ancestorID string) (bool, error) {
+	currentID := institutionID
+	for depth := 0; depth < maxUnitDepth; depth++ {
+		if currentID == ancestorID {
+			return true, nil
+		}
+		unitRelations, err := c.GetRelatedEntities(currentID, &models.Relationship{
+			Name:      "AS_UNIT",
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to get unit relationships: %w", err)
+		}
+		activeUnitRelations := activeRelationships(unitRelations)
+		if len(activeUnitRelations) == 0 {
+			return false, nil
+		}
+		currentID = activeUnitRelations[0].RelatedEntityID
+	}
+	return false, fmt.Errorf("units nested deeper than %d levels below '%s'", maxUnitDepth, institutionID)
+}
+
+// getActiveInstitutionByPresident retrieves an institution of the given type by name that is currently held,
+// directly or through its parent institutions, by a minister under the given president
+func (c *Client) getActiveInstitutionByPresident(presidentName, name, institutionType, dateISO string) (*models.Entity, error) {
+	results, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: institutionType,
+		},
+		Name: name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %s: %w", institutionType, err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%s '%s' not found", institutionType, name)
+	}
+	if len(results) > 1 {
+		return nil, fmt.Errorf("multiple %s entities found with name '%s'", institutionType, name)
+	}
+	institution := results[0]
+
+	// The institution must be held by an active minister of the president
+	_, err = c.getOwningMinister(presidentName, institution.ID, dateISO)
+	if err != nil {
+		return nil, fmt.Errorf("%s '%s' is not active under president '%s': %w", institutionType, name, presidentName, err)
+	}
+
+	return &models.Entity{
+		ID:         institution.ID,
+		Kind:       institution.Kind,
+		Created:    institution.Created,
+		Terminated: institution.Terminated,
+		Name: models.TimeBasedValue{
+			Value: institution.Name,
+		},
+		Metadata:      []models.MetadataEntry{},
+		Attributes:    []models.AttributeEntry{},
+		Relationships: []models.RelationshipEntry{},
+	}, nil
+}
+
+// getInstitutionParentID resolves the parent an institution is attached to under a president, either a minister
+// or another institution
+func (c *Client) getInstitutionParentID(presidentName, parent, parentType, dateISO string) (string, error) {
+	if parentType == "minister" {
+		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, parent, dateISO)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent minister entity: %w", err)
+		}
+		return ministerEntity.ID, nil
+	}
+
+	if IsInstitutionType(parentType) {
+		institutionEntity, err := c.getActiveInstitutionByPresident(presidentName, parent, parentType, dateISO)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent %s entity: %w", parentType, err)
+		}
+		return institutionEntity.ID, nil
+	}
+
+	return "", fmt.Errorf("an institution must be attached to a minister or another institution, got parent_type: %s", parentType)
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"orgchart_nexoan/models"
+)
+
+// Lineage
+// Renames, merges and splits link an entity to the entities that replaced it (RENAMED_TO, MERGED_INTO and
+// SPLIT_INTO). The lineage of an entity follows these links backwards to its ancestry and forwards to its
+// descendants. Links are only followed in time order (later links for descendants, earlier links for the
+// ancestry), so an inactive department reused by a later rename (A renamed to B, then B renamed back to A)
+// is walked once per rename instead of looping.
+
+// lineageActions maps the lineage relationships to the change action they record
+var lineageActions = map[string]string{
+	"RENAMED_TO":  ChangeRenamed,
+	"MERGED_INTO": ChangeMerged,
+	"SPLIT_INTO":  ChangeSplit,
+}
+
+// lineageReader walks lineage links, caching entities and documents through a snapshot reader
+type lineageReader struct {
+	*snapshotReader
+	events  map[string]bool // relationship IDs already turned into events
+	members map[string]bool // IDs of the entities seen in the lineage
+}
+
+// GetLineage returns the ancestry and descendants of a minister or institution across renames, merges and
+// splits, with the periods each entity of the lineage was held
+func (c *Client) GetLineage(entityID string) (*models.Lineage, error) {
+	reader := &lineageReader{
+		snapshotReader: newSnapshotReader(c, "", ""),
+		events:         make(map[string]bool),
+		members:        make(map[string]bool),
+	}
+
+	entity, err := reader.entity(entityID)
+	if err != nil {
+		return nil, err
+	}
+	reader.members[entityID] = true
+
+	lineage := &models.Lineage{
+		ID:   entity.ID,
+		Name: entity.Name,
+		Type: entity.Kind.Minor,
+	}
+
+	lineage.Ancestors, err = reader.ancestors(entityID, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	reader.events = make(map[string]bool)
+	lineage.Descendants, err = reader.descendants(entityID, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	sortLineageEvents(lineage.Ancestors)
+	sortLineageEvents(lineage.Descendants)
+
+	var ids []string
+	for id := range reader.members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		lineageEntity, err := reader.lineageEntity(id)
+		if err != nil {
+			return nil, err
+		}
+		lineage.Entities = append(lineage.Entities, lineageEntity)
+	}
+	sort.SliceStable(lineage.Entities, func(i, j int) bool {
+		return firstPeriodStart(lineage.Entities[i]) < firstPeriodStart(lineage.Entities[j])
+	})
+
+	return lineage, nil
+}
+
+// GetLineageByName returns the lineage of every minister and institution with the given name. Ministers are
+// created per president, so a name can match several entities.
+func (c *Client) GetLineageByName(name string) ([]models.Lineage, error) {
+	var lineages []models.Lineage
+	for _, entityType := range append([]string{"minister"}, InstitutionTypes...) {
+		results, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: entityType,
+			},
+			Name: name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for %s '%s': %w", entityType, name, err)
+		}
+		for _, result := range results {
+			lineage, err := c.GetLineage(result.ID)
+			if err != nil {
+				return nil, err
+			}
+			lineages = append(lineages, *lineage)
+		}
+	}
+
+	if len(lineages) == 0 {
+		return nil, fmt.Errorf("minister or institution named '%s' %w", name, ErrNotFound)
+	}
+	return lineages, nil
+}
+
+// descendants follows the lineage links from an entity that start on or after the given date
+func (r *lineageReader) descendants(entityID, afterISO string, depth int) ([]models.LineageEvent, error) {
+	if depth >= maxLineageDepth {
+		return nil, fmt.Errorf("lineage of %s is longer than %d links", entityID, maxLineageDepth)
+	}
+
+	relations, err := r.client.getSuccessorRelationships(entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.LineageEvent
+	for _, rel := range relations {
+		if rel.StartTime < afterISO || r.events[rel.ID] {
+			continue
+		}
+		r.events[rel.ID] = true
+
+		event, err := r.event(rel.Name, entityID, rel.RelatedEntityID, rel.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+
+		later, err := r.descendants(rel.RelatedEntityID, rel.StartTime, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, later...)
+	}
+	return events, nil
+}
+
+// ancestors follows the lineage links into an entity that start on or before the given date (any date
+// when empty)
+func (r *lineageReader) ancestors(entityID, beforeISO string, depth int) ([]models.LineageEvent, error) {
+	if depth >= maxLineageDepth {
+		return nil, fmt.Errorf("lineage of %s is longer than %d links", entityID, maxLineageDepth)
+	}
+
+	var events []models.LineageEvent
+	for _, relType := range lineageRelTypes {
+		relations, err := r.client.GetRelatedEntities(entityID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, entityID, err)
+		}
+
+		for _, rel := range relations {
+			if (beforeISO != "" && rel.StartTime > beforeISO) || r.events[rel.ID] {
+				continue
+			}
+			r.events[rel.ID] = true
+
+			event, err := r.event(relType, rel.RelatedEntityID, entityID, rel.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+
+			earlier, err := r.ancestors(rel.RelatedEntityID, rel.StartTime, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, earlier...)
+		}
+	}
+	return events, nil
+}
+
+// event describes the lineage link from one entity to another on the given date
+func (r *lineageReader) event(relType, fromID, toID, dateISO string) (models.LineageEvent, error) {
+	from, err := r.entity(fromID)
+	if err != nil {
+		return models.LineageEvent{}, err
+	}
+	to, err := r.entity(toID)
+	if err != nil {
+		return models.LineageEvent{}, err
+	}
+	r.members[fromID] = true
+	r.members[toID] = true
+
+	event := models.LineageEvent{
+		Action:       lineageActions[relType],
+		Relationship: relType,
+		FromID:       fromID,
+		FromName:     from.Name,
+		ToID:         toID,
+		ToName:       to.Name,
+		Date:         dateISO,
+	}
+
+	// The replaced entity's holding ends on the date of the change
+	event.President, err = r.president(fromID, from.Kind.Minor, dateISO, true, 0)
+	if err != nil {
+		return models.LineageEvent{}, err
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range []string{fromID, toID} {
+		gazettes, err := r.sources(id, dateISO)
+		if err != nil {
+			return models.LineageEvent{}, err
+		}
+		for _, gazette := range gazettes {
+			if !seen[gazette] {
+				seen[gazette] = true
+				event.Gazettes = append(event.Gazettes, gazette)
+			}
+		}
+	}
+	sort.Strings(event.Gazettes)
+
+	return event, nil
+}
+
+// lineageEntity lists the periods an entity was held, with the president of each period
+func (r *lineageReader) lineageEntity(entityID string) (models.LineageEntity, error) {
+	entity, err := r.entity(entityID)
+	if err != nil {
+		return models.LineageEntity{}, err
+	}
+	lineageEntity := models.LineageEntity{
+		ID:   entity.ID,
+		Name: entity.Name,
+		Type: entity.Kind.Minor,
+	}
+
+	for _, relType := range holdingRelTypes(entity.Kind.Minor) {
+		relations, err := r.client.GetRelatedEntities(entityID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return models.LineageEntity{}, fmt.Errorf("failed to get %s relationships of %s: %w", relType, entityID, err)
+		}
+
+		for _, rel := range relations {
+			parent, err := r.entity(rel.RelatedEntityID)
+			if err != nil {
+				return models.LineageEntity{}, err
+			}
+			period := models.LineagePeriod{
+				Parent:    parent.Name,
+				StartTime: rel.StartTime,
+				EndTime:   rel.EndTime,
+			}
+			if relType == "AS_MINISTER" {
+				period.President = parent.Name
+			} else {
+				period.President, err = r.president(parent.ID, parent.Kind.Minor, rel.StartTime, false, 0)
+				if err != nil {
+					return models.LineageEntity{}, err
+				}
+			}
+			lineageEntity.Periods = append(lineageEntity.Periods, period)
+		}
+	}
+
+	sort.SliceStable(lineageEntity.Periods, func(i, j int) bool {
+		return lineageEntity.Periods[i].StartTime < lineageEntity.Periods[j].StartTime
+	})
+	return lineageEntity, nil
+}
+
+// sortLineageEvents orders lineage events by date, then by the names of the entities
+func sortLineageEvents(events []models.LineageEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Date != events[j].Date {
+			return events[i].Date < events[j].Date
+		}
+		if events[i].FromName != events[j].FromName {
+			return events[i].FromName < events[j].FromName
+		}
+		return events[i].ToName < events[j].ToName
+	})
+}
+
+// firstPeriodStart returns the start of the first period an entity was held
+func firstPeriodStart(entity models.LineageEntity) string {
+	if len(entity.Periods) == 0 {
+		return ""
+	}
+	return entity.Periods[0].StartTime
+}
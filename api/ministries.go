@@ -0,0 +1,206 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Minister categories
+const (
+	MinisterCategoryCabinet    = "cabinet"
+	MinisterCategoryState      = "state"
+	MinisterCategoryNonCabinet = "non-cabinet"
+	MinisterCategoryProject    = "project"
+)
+
+// Minister classification attributes
+const (
+	MinisterCategoryAttribute        = "category"
+	MinisterGazettePositionAttribute = "gazette_position"
+)
+
+// ministerCategories lists the valid minister categories
+var ministerCategories = []string{MinisterCategoryCabinet, MinisterCategoryState, MinisterCategoryNonCabinet, MinisterCategoryProject}
+
+// InferMinisterCategory derives the category of a minister from its name, e.g. "State Minister of ..."
+// is a state minister. Names without a recognised prefix are cabinet ministers.
+func InferMinisterCategory(name string) string {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case strings.HasPrefix(normalized, "state minister"):
+		return MinisterCategoryState
+	case strings.HasPrefix(normalized, "project minister"):
+		return MinisterCategoryProject
+	case strings.HasPrefix(normalized, "non-cabinet minister"), strings.HasPrefix(normalized, "non cabinet minister"):
+		return MinisterCategoryNonCabinet
+	default:
+		return MinisterCategoryCabinet
+	}
+}
+
+// ministerClassification builds the classification attributes of a new minister from the optional
+// "category" and "gazette_position" columns, inferring the category from the name when it is not given
+func ministerClassification(transaction map[string]interface{}, name, dateISO string) ([]models.AttributeEntry, error) {
+	category, _ := transaction[MinisterCategoryAttribute].(string)
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		category = InferMinisterCategory(name)
+	}
+	valid := false
+	for _, allowed := range ministerCategories {
+		if category == allowed {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("unknown minister category '%s', must be one of: %s", category, strings.Join(ministerCategories, ", "))
+	}
+
+	attributes := []models.AttributeEntry{
+		{
+			Key: MinisterCategoryAttribute,
+			Value: models.AttributeValueCollection{
+				Values: []models.TimeBasedValue{
+					{
+						StartTime: dateISO,
+						Value:     category,
+					},
+				},
+			},
+		},
+	}
+
+	position, _ := transaction[MinisterGazettePositionAttribute].(string)
+	if position = strings.TrimSpace(position); position != "" {
+		positionValue, err := strconv.Atoi(position)
+		if err != nil || positionValue <= 0 {
+			return nil, fmt.Errorf("gazette_position must be a positive number, got '%s'", position)
+		}
+		attributes = append(attributes, models.AttributeEntry{
+			Key: MinisterGazettePositionAttribute,
+			Value: models.AttributeValueCollection{
+				Values: []models.TimeBasedValue{
+					{
+						StartTime: dateISO,
+						Value:     positionValue,
+					},
+				},
+			},
+		})
+	}
+
+	return attributes, nil
+}
+
+// inheritClassification copies the classification of an existing minister into the transaction creating its
+// successor, so a renamed or merged minister keeps its category and gazette position unless the transaction
+// gives new ones
+func (c *Client) inheritClassification(ministerID, dateISO string, from, to map[string]interface{}) {
+	for _, attribute := range []string{MinisterCategoryAttribute, MinisterGazettePositionAttribute} {
+		if value, ok := from[attribute].(string); ok && strings.TrimSpace(value) != "" {
+			to[attribute] = value
+			continue
+		}
+		// Ministers added before classification was recorded have no value to inherit
+		value, err := c.GetAttributeAsOf(ministerID, attribute, dateISO)
+		if err != nil {
+			fmt.Printf("Warning: could not read %s of minister %s, it is not carried over: %v\n", attribute, ministerID, err)
+			continue
+		}
+		if value != "" {
+			to[attribute] = value
+		}
+	}
+}
+
+// GetMinisterClassification returns the classification of a minister as of the given date
+func (c *Client) GetMinisterClassification(ministerID, ministerName, dateISO string) (models.MinisterClassification, error) {
+	classification := models.MinisterClassification{
+		ID:   ministerID,
+		Name: ministerName,
+	}
+
+	category, err := c.GetAttributeAsOf(ministerID, MinisterCategoryAttribute, dateISO)
+	if err != nil {
+		return classification, fmt.Errorf("failed to get category of minister %s: %w", ministerID, err)
+	}
+	if category == "" {
+		// Ministers added before classification was recorded are classified by name
+		category = InferMinisterCategory(ministerName)
+	}
+	classification.Category = category
+
+	position, err := c.GetAttributeAsOf(ministerID, MinisterGazettePositionAttribute, dateISO)
+	if err != nil {
+		return classification, fmt.Errorf("failed to get gazette position of minister %s: %w", ministerID, err)
+	}
+	if position != "" {
+		// Numbers may come back from the API as floats
+		if positionValue, err := strconv.ParseFloat(position, 64); err == nil {
+			classification.GazettePosition = int(positionValue)
+		}
+	}
+
+	return classification, nil
+}
+
+// GetMinistersByCategory returns the ministers of a president active on the given date, in gazette order.
+// An empty category returns every minister.
+func (c *Client) GetMinistersByCategory(presidentName, category, dateISO string) ([]models.MinisterClassification, error) {
+	presidentEntity, err := c.GetPresidentByGovernment(presidentName)
+	if err != nil {
+		return nil, err
+	}
+
+	presidentRelations, err := c.GetRelatedEntities(presidentEntity.ID, &models.Relationship{
+		Name: "AS_MINISTER",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get president's relationships: %w", err)
+	}
+
+	var ministers []models.MinisterClassification
+	for _, rel := range presidentRelations {
+		// Only consider ministers active on the date
+		if rel.StartTime > dateISO || (rel.EndTime != "" && rel.EndTime <= dateISO) {
+			continue
+		}
+
+		ministerResults, err := c.SearchEntities(&models.SearchCriteria{
+			ID: rel.RelatedEntityID,
+		})
+		if err != nil || len(ministerResults) == 0 || ministerResults[0].Kind.Minor != "minister" {
+			continue
+		}
+
+		classification, err := c.GetMinisterClassification(ministerResults[0].ID, ministerResults[0].Name, dateISO)
+		if err != nil {
+			return nil, err
+		}
+		if category == "" || classification.Category == category {
+			ministers = append(ministers, classification)
+		}
+	}
+
+	// Ministers without a gazette position are listed last, by name
+	sort.SliceStable(ministers, func(i, j int) bool {
+		pi, pj := ministers[i].GazettePosition, ministers[j].GazettePosition
+		if pi != pj {
+			if pi == 0 {
+				return false
+			}
+			if pj == 0 {
+				return true
+			}
+			return pi < pj
+		}
+		return ministers[i].Name < ministers[j].Name
+	})
+
+	return ministers, nil
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Minister policies for TransitionPresidency, given in the "ministers" column of a TRANSITION row
+const (
+	MinistersTransfer  = "transfer"  // active ministers move to the incoming president (default)
+	MinistersTerminate = "terminate" // active ministers are terminated with the outgoing president
+)
+
+// TransitionPresidency hands the government over from one president to another.
+// It ends the outgoing president's AS_PRESIDENT relationship with the government, creates the incoming
+// one (adding the citizen if needed), and transfers or terminates every active minister of the outgoing
+// president according to the "ministers" column. Transferred ministers are moved with MoveMinister using
+// the "appointees" column.
+func (c *Client) TransitionPresidency(transaction map[string]interface{}, entityCounters map[string]int) (*models.TransitionSummary, error) {
+	// Extract details from the transaction
+	oldPresident, ok := transaction["old_president"].(string)
+	if !ok || oldPresident == "" {
+		return nil, fmt.Errorf("old_president is required and must be a non-empty string")
+	}
+	newPresident, ok := transaction["new_president"].(string)
+	if !ok || newPresident == "" {
+		return nil, fmt.Errorf("new_president is required and must be a non-empty string")
+	}
+	dateStr := transaction["date"].(string)
+	transactionID := transaction["transaction_id"].(string)
+
+	ministersPolicy := MinistersTransfer
+	if value, ok := transaction["ministers"].(string); ok && strings.TrimSpace(value) != "" {
+		ministersPolicy = strings.ToLower(strings.TrimSpace(value))
+	}
+	if ministersPolicy != MinistersTransfer && ministersPolicy != MinistersTerminate {
+		return nil, fmt.Errorf("invalid ministers policy '%s', must be one of: %s, %s", ministersPolicy, MinistersTransfer, MinistersTerminate)
+	}
+
+	appointees := AppointeesKeep
+	if value, ok := transaction["appointees"].(string); ok && strings.TrimSpace(value) != "" {
+		appointees = strings.ToLower(strings.TrimSpace(value))
+	}
+	if appointees != AppointeesKeep && appointees != AppointeesTerminate && appointees != AppointeesReassign {
+		return nil, fmt.Errorf("invalid appointees option '%s', must be one of: %s, %s, %s", appointees, AppointeesKeep, AppointeesTerminate, AppointeesReassign)
+	}
+
+	// Parse the date
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(dateStr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date: %w", err)
+	}
+	dateISO := date.Format(time.RFC3339)
+
+	summary := &models.TransitionSummary{
+		OutgoingPresident: oldPresident,
+		IncomingPresident: newPresident,
+		Date:              dateISO,
+		AppointeesPolicy:  appointees,
+	}
+
+	// Get the government node
+	governmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for government entity: %w", err)
+	}
+	if len(governmentResults) == 0 {
+		return nil, fmt.Errorf("government entity not found")
+	}
+	government := governmentResults[0]
+
+	// Get the outgoing president
+	oldPresidentEntity, err := c.GetPresidentByGovernment(oldPresident)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outgoing president: %w", err)
+	}
+	oldPresidentID := oldPresidentEntity.ID
+
+	// Resolve every active minister of the outgoing president before anything is written, so a minister that
+	// cannot be transferred stops the transition with the presidency untouched
+	ministerRelations, err := c.GetRelatedEntities(oldPresidentID, &models.Relationship{
+		Name: "AS_MINISTER",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outgoing president's ministers: %w", err)
+	}
+
+	type outgoingMinister struct {
+		name        string
+		departments int
+	}
+	var ministers []outgoingMinister
+	for _, rel := range activeRelationships(ministerRelations) {
+		ministerResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for minister: %w", err)
+		}
+		if len(ministerResults) == 0 {
+			return nil, fmt.Errorf("failed to find minister with ID: %s", rel.RelatedEntityID)
+		}
+		minister := outgoingMinister{name: ministerResults[0].Name}
+
+		if ministersPolicy == MinistersTransfer {
+			departments, err := c.getMovableDepartments(rel.RelatedEntityID, minister.name)
+			if err != nil {
+				return nil, fmt.Errorf("cannot transfer minister '%s': %w", minister.name, err)
+			}
+			minister.departments = len(departments)
+		}
+		ministers = append(ministers, minister)
+	}
+
+	// 1. End the outgoing president's AS_PRESIDENT relationship
+	presidentRelations, err := c.GetRelatedEntities(government.ID, &models.Relationship{
+		Name:            "AS_PRESIDENT",
+		RelatedEntityID: oldPresidentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outgoing president's relationship: %w", err)
+	}
+	activePresidentRelations := activeRelationships(presidentRelations)
+	if len(activePresidentRelations) == 0 {
+		return nil, fmt.Errorf("no active AS_PRESIDENT relationship found for outgoing president '%s'", oldPresident)
+	}
+	for _, rel := range activePresidentRelations {
+		err = c.terminateRelationship(government.ID, rel.ID, dateISO)
+		if err != nil {
+			return nil, fmt.Errorf("failed to end outgoing president's term: %w", err)
+		}
+	}
+
+	// 2. Create the incoming president's AS_PRESIDENT relationship, unless one is already active
+	incomingActive := false
+	if newPresidentEntity, err := c.GetPresidentByGovernment(newPresident); err == nil {
+		newPresidentRelations, err := c.GetRelatedEntities(government.ID, &models.Relationship{
+			Name:            "AS_PRESIDENT",
+			RelatedEntityID: newPresidentEntity.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get incoming president's relationship: %w", err)
+		}
+		incomingActive = len(activeRelationships(newPresidentRelations)) > 0
+	}
+	if !incomingActive {
+		if _, exists := entityCounters["citizen"]; !exists {
+			entityCounters["citizen"] = 0
+		}
+		addPresidentTransaction := map[string]interface{}{
+			"parent":         government.Name,
+			"child":          newPresident,
+			"date":           dateStr,
+			"parent_type":    "government",
+			"child_type":     "citizen",
+			"rel_type":       "AS_PRESIDENT",
+			"transaction_id": transactionID,
+		}
+		entityCounters["citizen"], err = c.AddPersonEntity(addPresidentTransaction, entityCounters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add incoming president: %w", err)
+		}
+	}
+
+	// 3. Transfer or terminate every active minister of the outgoing president
+	for _, minister := range ministers {
+		if ministersPolicy == MinistersTransfer {
+			moveTransaction := map[string]interface{}{
+				"old_parent":     oldPresident,
+				"new_parent":     newPresident,
+				"child":          minister.name,
+				"type":           "minister",
+				"date":           dateStr,
+				"appointees":     appointees,
+				"transaction_id": transactionID,
+			}
+			err = c.MoveMinister(moveTransaction)
+			if err != nil {
+				return nil, fmt.Errorf("failed to transfer minister '%s': %w", minister.name, err)
+			}
+
+			summary.MinistersTransferred = append(summary.MinistersTransferred, minister.name)
+			summary.DepartmentsCarried += minister.departments
+		} else {
+			terminateTransaction := map[string]interface{}{
+				"parent":         oldPresident,
+				"child":          minister.name,
+				"date":           dateStr,
+				"parent_type":    "president",
+				"child_type":     "minister",
+				"rel_type":       "AS_MINISTER",
+				"president":      oldPresident,
+				"transaction_id": transactionID,
+			}
+			err = c.TerminateOrgEntity(terminateTransaction)
+			if err != nil {
+				return nil, fmt.Errorf("failed to terminate minister '%s': %w", minister.name, err)
+			}
+
+			summary.MinistersTerminated = append(summary.MinistersTerminated, minister.name)
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// President aliases
+// CSVs refer to presidents by full name, by short codes such as ":RW" or by directory names such as "rw".
+// Aliases are resolved to the canonical name when transactions are loaded. Built-in aliases cover the
+// executive presidents of Sri Lanka; an alias file adds more or overrides them.
+
+// builtinPresidentAliases lists the aliases of each canonical president name
+var builtinPresidentAliases = map[string][]string{
+	"J. R. Jayewardene":                  {"JRJ"},
+	"Ranasinghe Premadasa":               {"RP"},
+	"D. B. Wijetunga":                    {"DBW"},
+	"Chandrika Bandaranaike Kumaratunga": {"CBK"},
+	"Mahinda Rajapaksa":                  {"MR"},
+	"Maithripala Sirisena":               {"MS"},
+	"Gotabaya Rajapaksa":                 {"GR"},
+	"Ranil Wickremesinghe":               {"RW"},
+	"Anura Kumara Dissanayake":           {"AK", "AKD", "Anura Kumara"},
+}
+
+// presidentColumns are the transaction columns holding a president name
+var presidentColumns = []string{
+	"president",
+	"old_president",
+	"new_president",
+	"old_president_name",
+	"new_president_name",
+	"old_parent_pres",
+	"new_parent_pres",
+}
+
+// PresidentAliases resolves president aliases to canonical president names
+type PresidentAliases struct {
+	names map[string]string // normalized alias -> canonical name
+}
+
+// NewPresidentAliases creates a registry with the built-in aliases plus the given alias -> name entries.
+// Given entries override built-in ones.
+func NewPresidentAliases(aliases map[string]string) (*PresidentAliases, error) {
+	registry := &PresidentAliases{names: make(map[string]string)}
+	for name, builtin := range builtinPresidentAliases {
+		registry.names[normalizePresidentAlias(name)] = name
+		for _, alias := range builtin {
+			registry.names[normalizePresidentAlias(alias)] = name
+		}
+	}
+
+	for alias, name := range aliases {
+		key := normalizePresidentAlias(alias)
+		name = strings.TrimSpace(name)
+		if key == "" {
+			return nil, fmt.Errorf("president alias for '%s' is empty", name)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("president alias '%s' has no name", alias)
+		}
+		if strings.HasPrefix(name, ":") {
+			return nil, fmt.Errorf("president alias '%s' must name a president, not another alias (%s)", alias, name)
+		}
+		registry.names[key] = name
+		registry.names[normalizePresidentAlias(name)] = name
+	}
+
+	return registry, nil
+}
+
+// LoadPresidentAliases loads a president alias file on top of the built-in aliases. The file is either a
+// JSON object of alias -> name or a CSV file with alias and name columns.
+func LoadPresidentAliases(path string) (*PresidentAliases, error) {
+	aliases := make(map[string]string)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read president aliases: %w", err)
+		}
+		if err := json.Unmarshal(data, &aliases); err != nil {
+			return nil, fmt.Errorf("failed to parse president aliases: %w", err)
+		}
+
+	case ".csv":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open president aliases: %w", err)
+		}
+		defer file.Close()
+
+		records, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read president aliases: %w", err)
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("president aliases file %s is empty", path)
+		}
+
+		columns := make(map[string]int)
+		for i, header := range records[0] {
+			columns[strings.TrimSpace(header)] = i
+		}
+		for _, required := range []string{"alias", "name"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("president aliases file %s is missing the %s column", path, required)
+			}
+		}
+
+		for _, record := range records[1:] {
+			alias := record[columns["alias"]]
+			name := record[columns["name"]]
+			if existing, ok := aliases[alias]; ok && existing != name {
+				return nil, fmt.Errorf("president alias '%s' is given for both '%s' and '%s'", alias, existing, name)
+			}
+			aliases[alias] = name
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported president aliases format '%s', must be .json or .csv", filepath.Ext(path))
+	}
+
+	return NewPresidentAliases(aliases)
+}
+
+// normalizePresidentAlias makes aliases comparable: ":RW", "rw" and " RW " are the same alias
+func normalizePresidentAlias(alias string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(alias), ":"))
+}
+
+// Resolve returns the canonical name of a president alias. Names that are not aliases are returned
+// unchanged, except short codes starting with ":" which must be known.
+func (a *PresidentAliases) Resolve(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if name, ok := a.names[normalizePresidentAlias(value)]; ok {
+		return name, nil
+	}
+	if strings.HasPrefix(value, ":") {
+		return "", fmt.Errorf("unknown president alias '%s'", value)
+	}
+	return value, nil
+}
+
+// Names returns the canonical president names known to the registry
+func (a *PresidentAliases) Names() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range a.names {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolvePresidentColumns replaces the president aliases in a transaction with canonical names. Besides the
+// president columns this covers the child of AS_PRESIDENT rows and parents of type "president".
+func (a *PresidentAliases) resolvePresidentColumns(transaction map[string]interface{}) error {
+	columns := append([]string{}, presidentColumns...)
+	if relType, _ := transaction["rel_type"].(string); relType == "AS_PRESIDENT" {
+		columns = append(columns, "child")
+	}
+	if parentType, _ := transaction["parent_type"].(string); parentType == "president" {
+		columns = append(columns, "parent")
+	}
+
+	for _, column := range columns {
+		value, ok := transaction[column].(string)
+		if !ok || value == "" {
+			continue
+		}
+		name, err := a.Resolve(value)
+		if err != nil {
+			return fmt.Errorf("column %s: %w", column, err)
+		}
+		transaction[column] = name
+	}
+	return nil
+}
+
+// SetPresidentAliases replaces the president aliases used when loading transactions
+func (c *Client) SetPresidentAliases(aliases *PresidentAliases) {
+	c.presidents = aliases
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// newRelationshipID builds a relationship ID from the parent and child IDs and the current timestamp,
+// following the same convention the entity operations use to keep relationship IDs unique
+func newRelationshipID(parentID, childID string) string {
+	currentTimestamp := strings.ReplaceAll(time.Now().Format(time.RFC3339), ":", "-")
+	return fmt.Sprintf("%s_%s_%s", parentID, childID, currentTimestamp)
+}
+
+// createRelationship adds a new relationship of the given type from the parent entity to the child entity
+func (c *Client) createRelationship(parentID, childID, relType, dateISO string) error {
+	uniqueRelationshipID := newRelationshipID(parentID, childID)
+
+	_, err := c.UpdateEntity(parentID, &models.Entity{
+		ID: parentID,
+		Relationships: []models.RelationshipEntry{
+			{
+				Key: uniqueRelationshipID,
+				Value: models.Relationship{
+					RelatedEntityID: childID,
+					StartTime:       dateISO,
+					EndTime:         "",
+					ID:              uniqueRelationshipID,
+					Name:            relType,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s relationship from %s to %s: %w", relType, parentID, childID, err)
+	}
+
+	return nil
+}
+
+// terminateRelationship sets the end time of an existing relationship owned by the given entity
+func (c *Client) terminateRelationship(ownerID, relationshipID, dateISO string) error {
+	_, err := c.UpdateEntity(ownerID, &models.Entity{
+		ID: ownerID,
+		Relationships: []models.RelationshipEntry{
+			{
+				Key: relationshipID,
+				Value: models.Relationship{
+					EndTime: dateISO,
+					ID:      relationshipID,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to terminate relationship %s: %w", relationshipID, err)
+	}
+
+	return nil
+}
+
+// activeRelationships filters the given relationships down to the ones that are still active (EndTime == "")
+func activeRelationships(relations []models.Relationship) []models.Relationship {
+	var active []models.Relationship
+	for _, rel := range relations {
+		if rel.EndTime == "" {
+			active = append(active, rel)
+		}
+	}
+	return active
+}
+
+// relationshipsActiveAt filters the given relationships down to the ones active on the date (RFC3339):
+// started on or before it and not ended by it
+func relationshipsActiveAt(relations []models.Relationship, dateISO string) []models.Relationship {
+	var active []models.Relationship
+	for _, rel := range relations {
+		if rel.StartTime <= dateISO && (rel.EndTime == "" || rel.EndTime > dateISO) {
+			active = append(active, rel)
+		}
+	}
+	return active
+}
+
+// relationshipsActiveBetween filters the given relationships down to the ones active at some point between
+// the two dates (RFC3339). For equal dates this is relationshipsActiveAt.
+func relationshipsActiveBetween(relations []models.Relationship, fromISO, toISO string) []models.Relationship {
+	var active []models.Relationship
+	for _, rel := range relations {
+		if rel.StartTime <= toISO && (rel.EndTime == "" || rel.EndTime > fromISO) {
+			active = append(active, rel)
+		}
+	}
+	return active
+}
+
+// parseNameList parses a list field such as "[Minister A; Minister B]" into its trimmed entries.
+// Semicolons are used as separators to avoid conflicts with commas inside names.
+func parseNameList(value string) []string {
+	trimmedStr := strings.Trim(strings.TrimSpace(value), "[]")
+	var names []string
+	for _, name := range strings.Split(trimmedStr, ";") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// getActiveMinisterOfDepartment finds the minister under the given president that currently holds the department.
+// Returns the minister entity and the active AS_DEPARTMENT relationship linking the two.
+func (c *Client) getActiveMinisterOfDepartment(presidentName, departmentID, dateISO string) (*models.Entity, *models.Relationship, error) {
+	departmentRelations, err := c.GetRelatedEntities(departmentID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get department relationships: %w", err)
+	}
+
+	for _, rel := range activeRelationships(departmentRelations) {
+		ministerResults, err := c.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+		if err != nil || len(ministerResults) == 0 {
+			continue
+		}
+		minister := ministerResults[0]
+
+		// Check if this minister is under the specified president
+		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, minister.Name, dateISO)
+		if err == nil && ministerEntity.ID == minister.ID {
+			activeRel := rel
+			return ministerEntity, &activeRel, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no active minister relationship found for department '%s' under president '%s'", departmentID, presidentName)
+}
+
+// resolveEntityID finds the ID of an entity by name and type, using the same president-scoped lookups as
+// the entity operations. Unlike the termination lookups it also resolves entities whose relationships have
+// already ended, preferring an active match when one exists.
+func (c *Client) resolveEntityID(name, entityType, presidentName, dateISO string) (string, error) {
+	switch entityType {
+	case "president":
+		presidentEntity, err := c.GetPresidentByGovernment(name)
+		if err != nil {
+			return "", err
+		}
+		return presidentEntity.ID, nil
+
+	case "minister":
+		if presidentName == "" {
+			return "", fmt.Errorf("president name is required to resolve minister '%s'", name)
+		}
+		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, name, dateISO)
+		if err == nil {
+			return ministerEntity.ID, nil
+		}
+		ministerEntity, err = c.GetMinisterByPresident(presidentName, name, dateISO)
+		if err != nil {
+			return "", err
+		}
+		return ministerEntity.ID, nil
+
+	case "citizen":
+		// Resolved through the person registry when one is loaded
+		return c.getPersonID(name, nil)
+
+	default:
+		// Institutions are looked up under the president first, then by name alone
+		if IsInstitutionType(entityType) && presidentName != "" {
+			institution, err := c.getActiveInstitutionByPresident(presidentName, name, entityType, dateISO)
+			if err == nil {
+				return institution.ID, nil
+			}
+		}
+
+		majorType := "Organisation"
+		searchResults, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: majorType,
+				Minor: entityType,
+			},
+			Name: name,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to search for %s entity: %w", entityType, err)
+		}
+		if len(searchResults) == 0 {
+			return "", fmt.Errorf("%s entity not found: %s", entityType, name)
+		}
+		if len(searchResults) > 1 && entityType != "government" {
+			return "", fmt.Errorf("multiple %s entities found with name '%s'", entityType, name)
+		}
+		return searchResults[0].ID, nil
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// AppointmentRole describes a position a person can hold on an organisation entity
+type AppointmentRole struct {
+	Name        string   // role name used in the "role" column
+	RelType     string   // relationship created from the parent entity to the person
+	ParentTypes []string // entity kinds (Kind.Minor) the role can be attached to
+}
+
+// appointmentRoles lists the supported roles by name.
+// AS_APPOINTED is kept as the relationship of cabinet ministers so existing data keeps its meaning.
+var appointmentRoles = map[string]AppointmentRole{
+	"president":        {Name: "president", RelType: "AS_PRESIDENT", ParentTypes: []string{"government"}},
+	"prime_minister":   {Name: "prime_minister", RelType: "AS_PRIME_MINISTER", ParentTypes: []string{"government"}},
+	"cabinet_minister": {Name: "cabinet_minister", RelType: "AS_APPOINTED", ParentTypes: []string{"minister"}},
+	"state_minister":   {Name: "state_minister", RelType: "AS_STATE_MINISTER", ParentTypes: []string{"minister"}},
+	"deputy_minister":  {Name: "deputy_minister", RelType: "AS_DEPUTY_MINISTER", ParentTypes: []string{"minister"}},
+	"secretary":        {Name: "secretary", RelType: "AS_SECRETARY", ParentTypes: []string{"minister"}},
+	"department_head":  {Name: "department_head", RelType: "AS_HEAD", ParentTypes: InstitutionTypes},
+}
+
+// GetAppointmentRole returns the role with the given name
+func GetAppointmentRole(name string) (AppointmentRole, error) {
+	role, ok := appointmentRoles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return AppointmentRole{}, fmt.Errorf("unknown role '%s', must be one of: %s", name, strings.Join(appointmentRoleNames(), ", "))
+	}
+	return role, nil
+}
+
+// GetAppointmentRoleByRelType returns the role that is stored with the given relationship type
+func GetAppointmentRoleByRelType(relType string) (AppointmentRole, error) {
+	for _, role := range appointmentRoles {
+		if role.RelType == relType {
+			return role, nil
+		}
+	}
+	return AppointmentRole{}, fmt.Errorf("unknown appointment relationship type '%s'", relType)
+}
+
+// AllowsParent reports whether the role can be attached to an entity of the given kind
+func (r AppointmentRole) AllowsParent(parentType string) bool {
+	for _, allowed := range r.ParentTypes {
+		if allowed == parentType {
+			return true
+		}
+	}
+	return false
+}
+
+// appointmentRelTypes returns the relationship types of all roles that can be attached to the given entity kind
+func appointmentRelTypes(parentType string) []string {
+	var relTypes []string
+	for _, name := range appointmentRoleNames() {
+		role := appointmentRoles[name]
+		if role.AllowsParent(parentType) {
+			relTypes = append(relTypes, role.RelType)
+		}
+	}
+	return relTypes
+}
+
+// appointeeRelTypes returns the relationships appointing people to the government, ministers and institutions,
+// every role but the president's
+func appointeeRelTypes() map[string]bool {
+	relTypes := make(map[string]bool)
+	for _, role := range appointmentRoles {
+		if role.RelType != "AS_PRESIDENT" {
+			relTypes[role.RelType] = true
+		}
+	}
+	return relTypes
+}
+
+// appointmentRoleNames returns the names of the supported roles in a stable order
+func appointmentRoleNames() []string {
+	var names []string
+	for name := range appointmentRoles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveAppointmentRole determines the role of a person transaction from its "role" and/or "rel_type"
+// columns and validates that the role can be attached to the given parent type
+func resolveAppointmentRole(transaction map[string]interface{}, roleKey, parentType string) (AppointmentRole, error) {
+	roleName, _ := transaction[roleKey].(string)
+	relType, _ := transaction["rel_type"].(string)
+	roleName = strings.TrimSpace(roleName)
+	relType = strings.TrimSpace(relType)
+
+	var role AppointmentRole
+	var err error
+	switch {
+	case roleName != "":
+		role, err = GetAppointmentRole(roleName)
+		if err != nil {
+			return AppointmentRole{}, err
+		}
+		if relType != "" && relType != role.RelType {
+			return AppointmentRole{}, fmt.Errorf("rel_type '%s' does not match role '%s' (expected '%s')", relType, role.Name, role.RelType)
+		}
+	case relType != "":
+		role, err = GetAppointmentRoleByRelType(relType)
+		if err != nil {
+			return AppointmentRole{}, err
+		}
+	default:
+		role = appointmentRoles["cabinet_minister"]
+	}
+
+	if !role.AllowsParent(parentType) {
+		return AppointmentRole{}, fmt.Errorf("role '%s' cannot be attached to a %s, allowed: %s", role.Name, parentType, strings.Join(role.ParentTypes, ", "))
+	}
+
+	return role, nil
+}
+
+// getActiveDepartmentByPresident retrieves a department by name that is currently held by a minister under the given president
+func (c *Client) getActiveDepartmentByPresident(presidentName, departmentName, dateISO string) (*models.Entity, error) {
+	return c.getActiveInstitutionByPresident(presidentName, departmentName, InstitutionDepartment, dateISO)
+}
+
+// getAppointmentParentID resolves the entity a person is appointed to, using the president-scoped lookups
+// for ministers and departments
+func (c *Client) getAppointmentParentID(parent, parentType, presidentName, dateISO string) (string, error) {
+	switch parentType {
+	case "minister":
+		if presidentName == "" {
+			return "", fmt.Errorf("president name is required and must be a non-empty string when appointing a person to a minister")
+		}
+		ministerEntity, err := c.GetActiveMinisterByPresident(presidentName, parent, dateISO)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent minister entity: %w", err)
+		}
+		return ministerEntity.ID, nil
+
+	case InstitutionDepartment, InstitutionStatutoryBoard, InstitutionPublicCorporation, InstitutionStateOwnedCompany:
+		if presidentName == "" {
+			return "", fmt.Errorf("president name is required and must be a non-empty string when appointing a person to a %s", parentType)
+		}
+		institutionEntity, err := c.getActiveInstitutionByPresident(presidentName, parent, parentType, dateISO)
+		if err != nil {
+			return "", fmt.Errorf("failed to get parent %s entity: %w", parentType, err)
+		}
+		return institutionEntity.ID, nil
+
+	default:
+		searchResults, err := c.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: parentType,
+			},
+			Name: parent,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to search for parent entity: %w", err)
+		}
+		if len(searchResults) == 0 {
+			return "", fmt.Errorf("parent entity not found: %s", parent)
+		}
+		return searchResults[0].ID, nil
+	}
+}
+
+// getActiveAppointments returns the active appointment relationships of every role that can be held on
+// an entity of the given kind
+func (c *Client) getActiveAppointments(entityID, entityType string) ([]models.Relationship, error) {
+	var appointments []models.Relationship
+	for _, relType := range appointmentRelTypes(entityType) {
+		relations, err := c.GetRelatedEntities(entityID, &models.Relationship{
+			Name: relType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range activeRelationships(relations) {
+			// Relationships returned without a name are assumed to be of the queried type
+			if rel.Name == "" {
+				rel.Name = relType
+			}
+			appointments = append(appointments, rel)
+		}
+	}
+	return appointments, nil
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"orgchart_nexoan/models"
+)
+
+// Snapshots
+// A snapshot is the org chart as it stood on a date (or over a period): the government with its presidents,
+// the ministers of each president, the institutions (and nested units) each minister held and the people
+// appointed to ministers and institutions. Only relationships active on the date (or at some point of the
+// period) are followed, so any past state can be rebuilt. Each node carries the start date of the
+// relationship linking it to its parent and the gazettes that relationship was sourced from.
+
+// snapshotTypeOrder orders the children of a snapshot node: appointees first, then ministers and institutions
+var snapshotTypeOrder = map[string]int{
+	"citizen":  0,
+	"minister": 1,
+}
+
+// snapshotReader walks the org chart over a period (a single date for point-in-time snapshots), caching
+// the entities and documents it has seen
+type snapshotReader struct {
+	client    *Client
+	fromISO   string
+	toISO     string
+	entities  map[string]models.SearchResult
+	documents map[string]string // document ID -> gazette number
+}
+
+// newSnapshotReader creates a reader for the period between the two dates (RFC3339)
+func newSnapshotReader(c *Client, fromISO, toISO string) *snapshotReader {
+	return &snapshotReader{
+		client:    c,
+		fromISO:   fromISO,
+		toISO:     toISO,
+		entities:  make(map[string]models.SearchResult),
+		documents: make(map[string]string),
+	}
+}
+
+// GetSnapshot returns the org chart as it stood on the date (RFC3339)
+func (c *Client) GetSnapshot(dateISO string) (*models.Snapshot, error) {
+	return c.GetSnapshotRange(dateISO, dateISO)
+}
+
+// GetSnapshotRange returns every part of the org chart that existed at some point between the two dates
+// (RFC3339). Relationships that ended within the period keep their end time; the snapshot's Until is the end
+// of the period.
+func (c *Client) GetSnapshotRange(fromISO, toISO string) (*models.Snapshot, error) {
+	if toISO < fromISO {
+		return nil, fmt.Errorf("the end date %s must not be before the start date %s", toISO, fromISO)
+	}
+
+	governmentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for government entity: %w", err)
+	}
+	if len(governmentResults) == 0 {
+		return nil, fmt.Errorf("government entity not found")
+	}
+	government := governmentResults[0]
+
+	reader := newSnapshotReader(c, fromISO, toISO)
+	reader.entities[government.ID] = government
+
+	children, err := reader.children(government.ID, government.Kind.Minor, "")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.Snapshot{
+		Date: fromISO,
+		Government: models.SnapshotNode{
+			ID:       government.ID,
+			Name:     government.Name,
+			Type:     government.Kind.Minor,
+			Children: children,
+		},
+	}
+	if toISO != fromISO {
+		snapshot.Until = toISO
+	}
+	return snapshot, nil
+}
+
+// snapshotChildRelTypes returns the relationships followed below an entity of the given kind, reached
+// through the given relationship. The government has its presidents and the people appointed to it (the
+// prime minister) below it. Only citizens reached as presidents have ministers below them.
+func snapshotChildRelTypes(entityType, reachedBy string) []string {
+	switch {
+	case entityType == "government":
+		return appointmentRelTypes(entityType)
+	case reachedBy == "AS_PRESIDENT":
+		return []string{"AS_MINISTER"}
+	case entityType == "minister":
+		return append(appointmentRelTypes(entityType), "AS_DEPARTMENT")
+	case IsInstitutionType(entityType):
+		return append(appointmentRelTypes(entityType), "AS_UNIT")
+	default:
+		return nil
+	}
+}
+
+// children returns the snapshot nodes below an entity, sorted by kind and name
+func (r *snapshotReader) children(entityID, entityType, reachedBy string) ([]models.SnapshotNode, error) {
+	var nodes []models.SnapshotNode
+	for _, relType := range snapshotChildRelTypes(entityType, reachedBy) {
+		query := &models.Relationship{
+			Name:      relType,
+			Direction: "OUTGOING",
+		}
+		if r.fromISO == r.toISO {
+			query.ActiveAt = r.fromISO
+		}
+		relations, err := r.client.GetRelatedEntities(entityID, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", relType, entityID, err)
+		}
+
+		for _, rel := range relationshipsActiveBetween(relations, r.fromISO, r.toISO) {
+			child, err := r.entity(rel.RelatedEntityID)
+			if err != nil {
+				return nil, err
+			}
+
+			node := models.SnapshotNode{
+				ID:           child.ID,
+				Name:         child.Name,
+				Type:         child.Kind.Minor,
+				Relationship: relType,
+				StartTime:    rel.StartTime,
+				EndTime:      rel.EndTime,
+			}
+			if role, err := GetAppointmentRoleByRelType(relType); err == nil && relType != "AS_PRESIDENT" {
+				node.Role = role.Name
+			}
+
+			node.Sources, err = r.sources(child.ID, rel.StartTime)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Children, err = r.children(child.ID, child.Kind.Minor, relType)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		orderI, okI := snapshotTypeOrder[nodes[i].Type]
+		orderJ, okJ := snapshotTypeOrder[nodes[j].Type]
+		if !okI {
+			orderI = len(snapshotTypeOrder)
+		}
+		if !okJ {
+			orderJ = len(snapshotTypeOrder)
+		}
+		if orderI != orderJ {
+			return orderI < orderJ
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes, nil
+}
+
+// entity fetches an entity by ID
+func (r *snapshotReader) entity(entityID string) (models.SearchResult, error) {
+	if entity, ok := r.entities[entityID]; ok {
+		return entity, nil
+	}
+	results, err := r.client.SearchEntities(&models.SearchCriteria{ID: entityID})
+	if err != nil {
+		return models.SearchResult{}, fmt.Errorf("failed to search for entity %s: %w", entityID, err)
+	}
+	if len(results) == 0 {
+		return models.SearchResult{}, fmt.Errorf("entity %w: %s", ErrNotFound, entityID)
+	}
+	r.entities[entityID] = results[0]
+	return results[0], nil
+}
+
+// sources returns the gazettes an entity was linked to on the given start date
+func (r *snapshotReader) sources(entityID, startTime string) ([]string, error) {
+	relations, err := r.client.GetSourceDocuments(entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	var gazettes []string
+	for _, rel := range relations {
+		if rel.StartTime != startTime {
+			continue
+		}
+		gazette, ok := r.documents[rel.RelatedEntityID]
+		if !ok {
+			gazette, err = r.client.documentName(rel.RelatedEntityID)
+			if err != nil {
+				return nil, err
+			}
+			r.documents[rel.RelatedEntityID] = gazette
+		}
+		gazettes = append(gazettes, gazette)
+	}
+	sort.Strings(gazettes)
+	return gazettes, nil
+}
+
+// holdingRelTypes returns the relationships an entity of the given kind is held by
+func holdingRelTypes(entityType string) []string {
+	if entityType == "minister" {
+		return []string{"AS_MINISTER"}
+	}
+	return []string{"AS_DEPARTMENT", "AS_UNIT"}
+}
+
+// president returns the president a minister or institution was held under on the date, walking up through
+// parent institutions. With endingOn, holdings that ended on the date count as well. Returns an empty string
+// when the entity was not held on the date.
+func (r *snapshotReader) president(entityID, entityType, dateISO string, endingOn bool, depth int) (string, error) {
+	if depth >= maxUnitDepth {
+		return "", nil
+	}
+
+	for _, relType := range holdingRelTypes(entityType) {
+		relations, err := r.client.GetRelatedEntities(entityID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get %s relationships of %s: %w", relType, entityID, err)
+		}
+
+		for _, rel := range relations {
+			held := rel.StartTime <= dateISO && (rel.EndTime == "" || rel.EndTime > dateISO || (endingOn && rel.EndTime == dateISO))
+			if !held {
+				continue
+			}
+			parent, err := r.entity(rel.RelatedEntityID)
+			if err != nil {
+				return "", err
+			}
+			if relType == "AS_MINISTER" {
+				return parent.Name, nil
+			}
+			president, err := r.president(parent.ID, parent.Kind.Minor, dateISO, endingOn, depth+1)
+			if err != nil || president != "" {
+				return president, err
+			}
+		}
+	}
+	return "", nil
+}
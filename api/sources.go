@@ -0,0 +1,109 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Transaction sources
+// Every transaction ID starts with the number of the gazette it was taken from ("2289-34_tr_01" comes from
+// gazette 2289-34). Entities changed by a transaction get a SOURCED_FROM relationship to the gazette's
+// Document entity, starting at the transaction date, so each change can be traced to its legal instrument.
+// Gazettes are loaded in "document" mode; a transaction whose gazette is not loaded is applied without a
+// source and a warning is printed.
+
+// SourcedFromRelType is the relationship from a changed entity to the Document of its source gazette
+const SourcedFromRelType = "SOURCED_FROM"
+
+// GazetteNumberFromTransactionID returns the gazette number a transaction ID refers to, using hyphens as the
+// document names do ("2156/15_tr_01" refers to gazette 2156-15). Returns an empty string when the ID has no
+// transaction suffix.
+func GazetteNumberFromTransactionID(transactionID string) string {
+	parts := strings.Split(strings.TrimSpace(transactionID), "_")
+	if len(parts) < 2 || parts[0] == "" {
+		return ""
+	}
+	return strings.ReplaceAll(parts[0], "/", "-")
+}
+
+// findSourceDocument looks up the Document entity of a gazette by number. Returns an empty ID when the gazette
+// is not loaded. Both are cached for the lifetime of the client, until a document of the gazette is added.
+func (c *Client) findSourceDocument(gazetteNumber string) (string, error) {
+	if documentID, ok := c.sourceDocuments[gazetteNumber]; ok {
+		return documentID, nil
+	}
+
+	documentResults, err := c.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Document",
+		},
+		Name: gazetteNumber,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search for document entity: %w", err)
+	}
+	if len(documentResults) > 1 {
+		return "", fmt.Errorf("multiple documents found for gazette %s", gazetteNumber)
+	}
+
+	var documentID string
+	if len(documentResults) == 1 {
+		documentID = documentResults[0].ID
+	}
+	if c.sourceDocuments == nil {
+		c.sourceDocuments = make(map[string]string)
+	}
+	c.sourceDocuments[gazetteNumber] = documentID
+	return documentID, nil
+}
+
+// recordSource links an entity changed by a transaction to the Document of the gazette named by the
+// transaction ID. Transactions without an ID (built internally) are skipped, and an entity is linked to the
+// same document at most once per date. A gazette that is not loaded is warned about once.
+func (c *Client) recordSource(transaction map[string]interface{}, entityID, dateISO string) error {
+	transactionID, _ := transaction["transaction_id"].(string)
+	gazetteNumber := GazetteNumberFromTransactionID(transactionID)
+	if gazetteNumber == "" || entityID == "" {
+		return nil
+	}
+
+	_, lookedUp := c.sourceDocuments[gazetteNumber]
+	documentID, err := c.findSourceDocument(gazetteNumber)
+	if err != nil {
+		return err
+	}
+	if documentID == "" {
+		if !lookedUp {
+			fmt.Printf("Warning: no document found for gazette %s, its transactions (first %s) are not linked to their source\n", gazetteNumber, transactionID)
+		}
+		return nil
+	}
+
+	existing, err := c.GetRelatedEntities(entityID, &models.Relationship{
+		Name:            SourcedFromRelType,
+		RelatedEntityID: documentID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get source relationships: %w", err)
+	}
+	for _, rel := range existing {
+		if rel.StartTime == dateISO {
+			return nil
+		}
+	}
+
+	return c.createRelationship(entityID, documentID, SourcedFromRelType, dateISO)
+}
+
+// GetSourceDocuments returns the Document entities an entity was changed by, with the date of each change
+func (c *Client) GetSourceDocuments(entityID string) ([]models.Relationship, error) {
+	relations, err := c.GetRelatedEntities(entityID, &models.Relationship{
+		Name: SourcedFromRelType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source relationships: %w", err)
+	}
+	return relations, nil
+}
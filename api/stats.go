@@ -0,0 +1,401 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"orgchart_nexoan/models"
+)
+
+// Statistics
+// Statistics are computed per president and calendar year from an entity graph, read through the Query API
+// (GetGraph) or from a graph export, so they can be recomputed offline. Changes are attributed to the president
+// holding the changed minister (or the minister of the changed department or appointment) on the date of the
+// change. Appointments carried over by a minister rename are neither appointments nor removals, and a person
+// leaving one position for another on the same day is counted as moved.
+
+// statsKey identifies a row of the statistics
+type statsKey struct {
+	president string
+	year      int
+}
+
+// graphIndex gives access to the relationships of a graph by entity
+type graphIndex struct {
+	entities map[string]models.GraphEntity
+	outgoing map[string][]models.GraphRelationship
+	incoming map[string][]models.GraphRelationship
+}
+
+// newGraphIndex indexes the relationships of a graph, ordered by start time
+func newGraphIndex(graph *models.Graph) *graphIndex {
+	index := &graphIndex{
+		entities: make(map[string]models.GraphEntity),
+		outgoing: make(map[string][]models.GraphRelationship),
+		incoming: make(map[string][]models.GraphRelationship),
+	}
+	for _, entity := range graph.Entities {
+		index.entities[entity.ID] = entity
+	}
+
+	relationships := append([]models.GraphRelationship{}, graph.Relationships...)
+	sort.SliceStable(relationships, func(i, j int) bool {
+		return relationships[i].StartTime < relationships[j].StartTime
+	})
+	for _, rel := range relationships {
+		index.outgoing[rel.From] = append(index.outgoing[rel.From], rel)
+		index.incoming[rel.To] = append(index.incoming[rel.To], rel)
+	}
+	return index
+}
+
+// related returns the relationships of the given type into (incoming) or out of an entity
+func (x *graphIndex) related(entityID, relType string, incoming bool) []models.GraphRelationship {
+	relations := x.outgoing[entityID]
+	if incoming {
+		relations = x.incoming[entityID]
+	}
+	var matches []models.GraphRelationship
+	for _, rel := range relations {
+		if rel.Name == relType {
+			matches = append(matches, rel)
+		}
+	}
+	return matches
+}
+
+// holdingAt returns the relationship holding a minister or institution on the date. With endingOn, holdings
+// that ended on the date count as well.
+func (x *graphIndex) holdingAt(entityID, dateISO string, endingOn bool) *models.GraphRelationship {
+	for _, relType := range holdingRelTypes(x.entities[entityID].Kind.Minor) {
+		relations := x.related(entityID, relType, true)
+		for i, rel := range relations {
+			if rel.StartTime <= dateISO && (rel.EndTime == "" || rel.EndTime > dateISO || (endingOn && rel.EndTime == dateISO)) {
+				return &relations[i]
+			}
+		}
+	}
+	return nil
+}
+
+// president returns the president holding a minister or institution on the date, walking up through
+// parent institutions, or the president in office for the government itself. With endingOn, holdings that
+// ended on the date count as well.
+func (x *graphIndex) president(entityID, dateISO string, endingOn bool) string {
+	if x.entities[entityID].Kind.Minor == "government" {
+		for _, term := range x.related(entityID, "AS_PRESIDENT", false) {
+			if term.StartTime <= dateISO && (term.EndTime == "" || term.EndTime > dateISO || (endingOn && term.EndTime == dateISO)) {
+				return x.entities[term.To].Name
+			}
+		}
+		return ""
+	}
+
+	currentID := entityID
+	for depth := 0; depth < maxUnitDepth; depth++ {
+		holder := x.holdingAt(currentID, dateISO, endingOn)
+		if holder == nil {
+			return ""
+		}
+		if holder.Name == "AS_MINISTER" {
+			return x.entities[holder.From].Name
+		}
+		currentID = holder.From
+	}
+	return ""
+}
+
+// hasRelationOn reports whether an entity has an outgoing relationship of the given type to another
+// entity starting on the date
+func (x *graphIndex) hasRelationOn(fromID, toID, relType, dateISO string) bool {
+	for _, rel := range x.related(fromID, relType, false) {
+		if rel.To == toID && rel.StartTime == dateISO {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStats computes the statistics of the graph read through the Query API
+func (c *Client) GetStats() ([]models.PresidencyStats, error) {
+	graph, err := c.GetGraph()
+	if err != nil {
+		return nil, err
+	}
+	return ComputeStats(graph), nil
+}
+
+// ComputeStats computes the statistics of a graph per president and calendar year, ordered by the start of
+// each period. Open presidential terms run until the time the graph was exported.
+func ComputeStats(graph *models.Graph) []models.PresidencyStats {
+	index := newGraphIndex(graph)
+	now := graph.ExportedAt
+	if now == "" {
+		now = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	rows := make(map[statsKey]*models.PresidencyStats)
+	row := func(president, dateISO string) *models.PresidencyStats {
+		key := statsKey{president: president, year: yearOf(dateISO)}
+		if rows[key] == nil {
+			rows[key] = &models.PresidencyStats{
+				President: president,
+				Year:      key.year,
+				From:      yearStart(key.year),
+				To:        yearStart(key.year + 1),
+			}
+		}
+		return rows[key]
+	}
+
+	appointmentTypes := appointeeRelTypes()
+	lifetimes := make(map[statsKey][]float64)
+
+	// One row per president and year of each presidential term, cut to the term
+	for _, entity := range graph.Entities {
+		if entity.Kind.Minor != "government" {
+			continue
+		}
+		for _, term := range index.related(entity.ID, "AS_PRESIDENT", false) {
+			president := index.entities[term.To].Name
+			end := term.EndTime
+			if end == "" {
+				end = now
+			}
+			for year := yearOf(term.StartTime); year <= yearOf(end); year++ {
+				from, to := maxString(term.StartTime, yearStart(year)), minString(end, yearStart(year+1))
+				if from >= to {
+					continue
+				}
+				r := row(president, from)
+				r.From, r.To = from, to
+			}
+		}
+	}
+
+	for _, entity := range graph.Entities {
+		switch {
+		case entity.Kind.Minor == "minister":
+			holdings := index.related(entity.ID, "AS_MINISTER", true)
+			if len(holdings) == 0 {
+				continue
+			}
+
+			// Ministers that do not replace another minister are created with their first holding
+			replaces := false
+			for _, relType := range lineageRelTypes {
+				if len(index.related(entity.ID, relType, true)) > 0 {
+					replaces = true
+				}
+			}
+			first := holdings[0]
+			if !replaces {
+				row(index.entities[first.From].Name, first.StartTime).MinistersCreated++
+			}
+
+			// Renames, merges and splits of the minister
+			successors := make(map[string]bool)
+			for _, relType := range lineageRelTypes {
+				counted := make(map[string]bool)
+				for _, rel := range index.related(entity.ID, relType, false) {
+					successors[rel.StartTime] = true
+					// A split links the minister to every new minister, it is counted once
+					if counted[rel.StartTime] {
+						continue
+					}
+					counted[rel.StartTime] = true
+
+					r := row(index.president(entity.ID, rel.StartTime, true), rel.StartTime)
+					switch relType {
+					case "RENAMED_TO":
+						r.MinistersRenamed++
+					case "MERGED_INTO":
+						r.MinistersMerged++
+					case "SPLIT_INTO":
+						r.MinistersSplit++
+					}
+				}
+			}
+
+			// Holdings that ended without a successor or a transfer to another president are terminations
+			var last *models.GraphRelationship
+			open := false
+			for i, holding := range holdings {
+				if holding.EndTime == "" {
+					open = true
+					continue
+				}
+				if last == nil || holding.EndTime > last.EndTime {
+					last = &holdings[i]
+				}
+				transferred := false
+				for _, other := range holdings {
+					if other.StartTime == holding.EndTime && other.ID != holding.ID {
+						transferred = true
+					}
+				}
+				if !successors[holding.EndTime] && !transferred {
+					row(index.entities[holding.From].Name, holding.EndTime).MinistersTerminated++
+				}
+			}
+
+			// The portfolio lasted from the first holding until the last one ended
+			if !open && last != nil {
+				key := statsKey{president: index.entities[last.From].Name, year: yearOf(last.EndTime)}
+				row(key.president, last.EndTime).PortfoliosEnded++
+				lifetimes[key] = append(lifetimes[key], daysBetween(first.StartTime, last.EndTime))
+			}
+
+		case IsInstitutionType(entity.Kind.Minor):
+			// A department re-homed to another minister on the day it left one was moved, unless the minister
+			// was renamed
+			holdings := index.related(entity.ID, "AS_DEPARTMENT", true)
+			for _, holding := range holdings {
+				if holding.EndTime == "" {
+					continue
+				}
+				for _, next := range holdings {
+					if next.StartTime != holding.EndTime || next.From == holding.From || index.hasRelationOn(holding.From, next.From, "RENAMED_TO", next.StartTime) {
+						continue
+					}
+					row(index.president(next.From, next.StartTime, false), next.StartTime).DepartmentsMoved++
+					break
+				}
+			}
+
+		case entity.Kind.Major == "Person":
+			var appointments []models.GraphRelationship
+			for _, rel := range index.incoming[entity.ID] {
+				if appointmentTypes[rel.Name] {
+					appointments = append(appointments, rel)
+				}
+			}
+			for _, appointment := range appointments {
+				// An appointment starting the day another one ended continues it
+				continued := false
+				for _, other := range appointments {
+					if other.EndTime != "" && other.EndTime == appointment.StartTime && other.ID != appointment.ID {
+						continued = true
+					}
+				}
+				if !continued {
+					row(index.president(appointment.From, appointment.StartTime, false), appointment.StartTime).PeopleAppointed++
+				}
+
+				if appointment.EndTime == "" {
+					continue
+				}
+				var next *models.GraphRelationship
+				for i, other := range appointments {
+					if other.StartTime == appointment.EndTime && other.ID != appointment.ID {
+						next = &appointments[i]
+						break
+					}
+				}
+				switch {
+				case next == nil:
+					row(index.president(appointment.From, appointment.EndTime, true), appointment.EndTime).PeopleRemoved++
+				case !index.hasRelationOn(appointment.From, next.From, "RENAMED_TO", appointment.EndTime):
+					row(index.president(next.From, next.StartTime, false), next.StartTime).PeopleMoved++
+				}
+			}
+		}
+	}
+
+	// Ministers and departments held at some point of each row's period
+	for _, r := range rows {
+		if r.President == "" {
+			continue
+		}
+		ministers := make(map[string]bool)
+		for _, entity := range graph.Entities {
+			if entity.Kind.Minor != "citizen" || entity.Name != r.President {
+				continue
+			}
+			for _, rel := range index.related(entity.ID, "AS_MINISTER", false) {
+				if overlaps(rel, r.From, r.To) {
+					ministers[rel.To] = true
+				}
+			}
+		}
+		r.ActiveMinisters = len(ministers)
+
+		departments := make(map[string]bool)
+		for ministerID := range ministers {
+			for _, rel := range index.related(ministerID, "AS_DEPARTMENT", false) {
+				if overlaps(rel, r.From, r.To) {
+					departments[rel.To] = true
+				}
+			}
+		}
+		r.ActiveDepartments = len(departments)
+
+		if days := lifetimes[statsKey{president: r.President, year: r.Year}]; len(days) > 0 {
+			total := 0.0
+			for _, d := range days {
+				total += d
+			}
+			r.AveragePortfolioDays = math.Round(total/float64(len(days))*10) / 10
+		}
+	}
+
+	var stats []models.PresidencyStats
+	for _, r := range rows {
+		stats = append(stats, *r)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].From != stats[j].From {
+			return stats[i].From < stats[j].From
+		}
+		return stats[i].President < stats[j].President
+	})
+	return stats
+}
+
+// overlaps reports whether a relationship was active at some point between from (inclusive) and to (exclusive)
+func overlaps(rel models.GraphRelationship, fromISO, toISO string) bool {
+	return rel.StartTime < toISO && (rel.EndTime == "" || rel.EndTime > fromISO)
+}
+
+// yearOf returns the year of an RFC3339 date
+func yearOf(dateISO string) int {
+	date, err := time.Parse(time.RFC3339, dateISO)
+	if err != nil {
+		return 0
+	}
+	return date.Year()
+}
+
+// yearStart returns the first instant of a year as RFC3339
+func yearStart(year int) string {
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+}
+
+// daysBetween returns the number of days between two RFC3339 dates
+func daysBetween(fromISO, toISO string) float64 {
+	from, err := time.Parse(time.RFC3339, fromISO)
+	if err != nil {
+		return 0
+	}
+	to, err := time.Parse(time.RFC3339, toISO)
+	if err != nil {
+		return 0
+	}
+	return to.Sub(from).Hours() / 24
+}
+
+// maxString and minString compare RFC3339 dates
+func maxString(a, b string) string {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minString(a, b string) string {
+	if a < b {
+		return a
+	}
+	return b
+}
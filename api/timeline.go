@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"orgchart_nexoan/models"
+)
+
+// Person timelines
+// The timeline of a person lists every appointment they held across presidencies and their presidential
+// terms. Operations do not record why an appointment ended, so the reason is inferred from what happened on
+// its end date: the minister was renamed, merged or split (its appointees are carried over or cut), the person
+// took another position (MovePerson), the minister itself was terminated, or the appointment alone was
+// terminated.
+
+// Appointment end reasons
+const (
+	AppointmentTerminated       = "terminated"
+	AppointmentMoved            = "moved"
+	AppointmentRenamed          = "renamed"
+	AppointmentMerged           = "merged"
+	AppointmentSplit            = "split"
+	AppointmentParentTerminated = "parent_terminated"
+)
+
+// successorEndReasons maps the lineage relationships to the end reason of the appointments they cut
+var successorEndReasons = map[string]string{
+	"RENAMED_TO":  AppointmentRenamed,
+	"MERGED_INTO": AppointmentMerged,
+	"SPLIT_INTO":  AppointmentSplit,
+}
+
+// GetPersonTimeline returns the appointments and presidential terms of a person, oldest first
+func (c *Client) GetPersonTimeline(personID string) (*models.PersonTimeline, error) {
+	reader := newSnapshotReader(c, "", "")
+	person, err := reader.entity(personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.Kind.Major != "Person" {
+		return nil, fmt.Errorf("entity %s is a %s, not a person", personID, person.Kind.Minor)
+	}
+
+	timeline := &models.PersonTimeline{
+		ID:           person.ID,
+		Name:         person.Name,
+		Appointments: []models.CareerAppointment{},
+	}
+
+	for _, name := range appointmentRoleNames() {
+		role := appointmentRoles[name]
+		relations, err := c.GetRelatedEntities(personID, &models.Relationship{
+			Name:      role.RelType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s relationships of %s: %w", role.RelType, personID, err)
+		}
+
+		for _, rel := range relations {
+			if role.RelType == "AS_PRESIDENT" {
+				timeline.Terms = append(timeline.Terms, models.PresidentialTerm{StartTime: rel.StartTime, EndTime: rel.EndTime})
+				continue
+			}
+
+			parent, err := reader.entity(rel.RelatedEntityID)
+			if err != nil {
+				return nil, err
+			}
+			appointment := models.CareerAppointment{
+				Role:         role.Name,
+				Relationship: role.RelType,
+				ParentID:     parent.ID,
+				ParentName:   parent.Name,
+				ParentType:   parent.Kind.Minor,
+				StartTime:    rel.StartTime,
+				EndTime:      rel.EndTime,
+			}
+			appointment.President, err = reader.president(parent.ID, parent.Kind.Minor, rel.StartTime, false, 0)
+			if err != nil {
+				return nil, err
+			}
+			appointment.Gazettes, err = reader.sources(personID, rel.StartTime)
+			if err != nil {
+				return nil, err
+			}
+			timeline.Appointments = append(timeline.Appointments, appointment)
+		}
+	}
+
+	for i := range timeline.Appointments {
+		if timeline.Appointments[i].EndTime == "" {
+			continue
+		}
+		if err := reader.explainEnd(&timeline.Appointments[i], timeline); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(timeline.Terms, func(i, j int) bool {
+		return timeline.Terms[i].StartTime < timeline.Terms[j].StartTime
+	})
+	sort.SliceStable(timeline.Appointments, func(i, j int) bool {
+		if timeline.Appointments[i].StartTime != timeline.Appointments[j].StartTime {
+			return timeline.Appointments[i].StartTime < timeline.Appointments[j].StartTime
+		}
+		return timeline.Appointments[i].ParentName < timeline.Appointments[j].ParentName
+	})
+	return timeline, nil
+}
+
+// GetPersonTimelineByName returns the timeline of the person with the given name, resolved through the person
+// registry like transactions are
+func (c *Client) GetPersonTimelineByName(name string) (*models.PersonTimeline, error) {
+	personID, err := c.getPersonID(name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetPersonTimeline(personID)
+}
+
+// explainEnd sets the reason an appointment ended and the entity it was carried or moved to
+func (r *snapshotReader) explainEnd(appointment *models.CareerAppointment, timeline *models.PersonTimeline) error {
+	// The minister or institution was renamed, merged or split on the end date
+	successors, err := r.client.getSuccessorRelationships(appointment.ParentID)
+	if err != nil {
+		return err
+	}
+	for _, rel := range successors {
+		if rel.StartTime != appointment.EndTime {
+			continue
+		}
+		successor, err := r.entity(rel.RelatedEntityID)
+		if err != nil {
+			return err
+		}
+		appointment.EndReason = successorEndReasons[rel.Name]
+		appointment.Successor = successor.Name
+		return nil
+	}
+
+	// The person took another position on the end date
+	for _, other := range timeline.Appointments {
+		if other.StartTime == appointment.EndTime && other.ParentID != appointment.ParentID {
+			appointment.EndReason = AppointmentMoved
+			appointment.Successor = other.ParentName
+			return nil
+		}
+	}
+	for _, term := range timeline.Terms {
+		if term.StartTime == appointment.EndTime {
+			appointment.EndReason = AppointmentMoved
+			appointment.Successor = "president"
+			return nil
+		}
+	}
+
+	// The minister or institution itself was terminated on the end date
+	for _, relType := range holdingRelTypes(appointment.ParentType) {
+		relations, err := r.client.GetRelatedEntities(appointment.ParentID, &models.Relationship{
+			Name:      relType,
+			Direction: "INCOMING",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get %s relationships of %s: %w", relType, appointment.ParentID, err)
+		}
+		for _, rel := range relations {
+			if rel.EndTime == appointment.EndTime {
+				appointment.EndReason = AppointmentParentTerminated
+				return nil
+			}
+		}
+	}
+
+	appointment.EndReason = AppointmentTerminated
+	return nil
+}
@@ -0,0 +1,838 @@
+package api
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Transaction export
+// A graph is turned back into the transaction CSVs the tool loads, laid out like the data tree: gazettes in
+// documents/<president>/<date>, organisation changes in orgchart/<president>/<date>, appointments in
+// people/<president>/<date> and presidential terms in people/<president>/<date>/presidency. Loading the
+// directories in order (documents, presidency, orgchart, people, then documents/<president>/<date>/links for
+// each date) into an empty backend rebuilds the graph, with the changes linked to their gazettes. Changes applied as part of another transaction are not
+// written again: holdings and appointments carried over by a rename, merge or split, appointments ended with
+// their minister and the appointees of a minister moved to another president. The transactions of a
+// directory are numbered in the order they must be applied, under the gazette most of them were sourced
+// from (the date when none was), so a rebuilt graph links each directory to a single gazette. Attributes are
+// written as columns of the ADD rows creating their entity (the classification of ministers and the person
+// attributes in effect when the person was created); later person attribute values are written as ATTRIBUTE
+// rows.
+
+// Phases of a date, in load order. Gazettes are loaded first so the changes of the date are linked to them;
+// gazettes belonging to entities created on the date, and the links between gazettes, are loaded last.
+const (
+	phaseDocuments = iota
+	phasePresidency
+	phaseOrgchart
+	phasePeople
+	phaseDocumentLinks
+)
+
+// transactionFileTypes orders the files of a directory
+var transactionFileTypes = []string{"ADD", "RENAME", "MERGE", "SPLIT", "MOVE", "TERMINATE", "ATTRIBUTE", "AMENDS", "CORRECTS",
+	"SUPERSEDES"}
+
+// documentMetadataColumns are the metadata keys written back as columns of document ADD files
+var documentMetadataColumns = []string{DocumentURLKey, DocumentDescriptionKey, DocumentGazetteNumberKey, DocumentPartKey,
+	DocumentLanguageKey, DocumentPagesKey}
+
+// isDocumentPhase reports whether a phase loads documents
+func isDocumentPhase(phase int) bool {
+	return phase == phaseDocuments || phase == phaseDocumentLinks
+}
+
+// transactionColumns returns the CSV columns of a transaction file of the given phase
+func transactionColumns(phase int, fileType string) []string {
+	switch {
+	case isDocumentPhase(phase) && fileType == "ADD":
+		return append([]string{"transaction_id", "date", "child_type", "child", "parent_type", "parent"}, documentMetadataColumns...)
+	case isDocumentPhase(phase):
+		return []string{"transaction_id", "document", "target", "date"}
+	case fileType == "ADD" || fileType == "TERMINATE":
+		return []string{"transaction_id", "parent", "parent_type", "child", "child_type", "rel_type", "date"}
+	case fileType == "MOVE" && phase == phasePeople:
+		return []string{"transaction_id", "old_parent", "old_parent_type", "old_president_name", "new_parent", "new_parent_type",
+			"new_president_name", "child", "type", "role", "old_role", "date"}
+	case fileType == "MOVE":
+		return []string{"transaction_id", "old_parent", "new_parent", "child", "type", "date", "old_president_name",
+			"new_president_name", "new_parent_type", "appointees"}
+	case fileType == "MERGE":
+		return []string{"transaction_id", "old", "new", "type", "date", "new_parent"}
+	case fileType == "SPLIT":
+		return []string{"transaction_id", "old", "new", "departments", "appointees", "date"}
+	case fileType == "ATTRIBUTE":
+		return []string{"transaction_id", "child", "attribute", "value", "date", "end_date"}
+	default:
+		return []string{"transaction_id", "old", "new", "type", "date"}
+	}
+}
+
+// optionalColumns returns the attribute columns given in the rows of a file, appended to its columns only when
+// a row has a value: the minister classification, the person attributes, then the additional "attr_" ones
+func optionalColumns(transactions []pendingTransaction, fileType string) []string {
+	given := make(map[string]bool)
+	for _, transaction := range transactions {
+		if transaction.fileType != fileType {
+			continue
+		}
+		for column := range transaction.values {
+			given[column] = true
+		}
+	}
+
+	var columns, additional []string
+	known := append([]string{MinisterCategoryAttribute, MinisterGazettePositionAttribute}, PersonAttributes...)
+	for _, column := range known {
+		if given[column] {
+			columns = append(columns, column)
+		}
+	}
+	for column := range given {
+		if strings.HasPrefix(column, attributeColumnPrefix) {
+			additional = append(additional, column)
+		}
+	}
+	sort.Strings(additional)
+	return append(columns, additional...)
+}
+
+// pendingTransaction is a transaction before it is numbered and written to its directory
+type pendingTransaction struct {
+	phase     int
+	president string
+	date      string // RFC3339
+	fileType  string
+	rank      int // order of application within the directory
+	gazettes  []string
+	values    map[string]string
+}
+
+// transactionBuilder collects the transactions of a graph
+type transactionBuilder struct {
+	*graphIndex
+	government   models.GraphEntity
+	transactions []pendingTransaction
+	applied      map[string]bool // relationships ended by another transaction
+	terminated   map[string]bool // ministers terminated, by ID and date
+	attributed   map[string]bool // people created with their attributes, and the values written as columns
+}
+
+// BuildTransactions returns the transaction directories that rebuild a graph, ordered by load order. With an
+// until date (RFC3339), only changes up to that date are written, so the files rebuild the graph as it stood
+// then. Documents that belong to no entity are not written, as a document row needs a parent.
+func BuildTransactions(graph *models.Graph, untilISO string) []models.TransactionDirectory {
+	if untilISO != "" {
+		graph = graphAsOf(graph, untilISO)
+	}
+	b := &transactionBuilder{
+		graphIndex: newGraphIndex(graph),
+		applied:    make(map[string]bool),
+		terminated: make(map[string]bool),
+		attributed: make(map[string]bool),
+	}
+
+	for _, entity := range graph.Entities {
+		if entity.Kind.Minor == "government" {
+			b.government = entity
+			b.presidency(entity)
+		}
+	}
+	for _, entity := range graph.Entities {
+		if entity.Kind.Major == "Document" {
+			b.documents(entity)
+		}
+	}
+	for _, entity := range graph.Entities {
+		if entity.Kind.Minor == "minister" || IsInstitutionType(entity.Kind.Minor) {
+			b.lineage(entity)
+			b.holdings(entity)
+		}
+	}
+	for _, entity := range graph.Entities {
+		if entity.Kind.Major == "Person" {
+			b.appointments(entity)
+			b.attributes(entity)
+		}
+	}
+
+	return b.directories()
+}
+
+// graphAsOf returns the graph as it stood on a date: later relationships and attribute values are left out,
+// and relationships and values ending after the date are open
+func graphAsOf(graph *models.Graph, untilISO string) *models.Graph {
+	cut := &models.Graph{ExportedAt: untilISO}
+	for _, entity := range graph.Entities {
+		attributes := entity.Attributes
+		entity.Attributes = nil
+		for name, values := range attributes {
+			for _, value := range values {
+				if value.StartTime > untilISO {
+					continue
+				}
+				if value.EndTime > untilISO {
+					value.EndTime = ""
+				}
+				if entity.Attributes == nil {
+					entity.Attributes = make(map[string][]models.TimeBasedValue)
+				}
+				entity.Attributes[name] = append(entity.Attributes[name], value)
+			}
+		}
+		cut.Entities = append(cut.Entities, entity)
+	}
+	for _, rel := range graph.Relationships {
+		if rel.StartTime > untilISO {
+			continue
+		}
+		if rel.EndTime > untilISO {
+			rel.EndTime = ""
+		}
+		cut.Relationships = append(cut.Relationships, rel)
+	}
+	return cut
+}
+
+// add queues a transaction, sourced from the gazettes of the given entities on its date
+func (b *transactionBuilder) add(phase int, president, dateISO, fileType string, rank int, values map[string]string, sourceIDs ...string) {
+	values["date"] = dateISO[:10]
+	transaction := pendingTransaction{
+		phase:     phase,
+		president: president,
+		date:      dateISO,
+		fileType:  fileType,
+		rank:      rank,
+		values:    values,
+	}
+	for _, id := range sourceIDs {
+		for _, rel := range b.related(id, SourcedFromRelType, false) {
+			if rel.StartTime == dateISO {
+				transaction.gazettes = append(transaction.gazettes, b.entities[rel.To].Name)
+			}
+		}
+	}
+	b.transactions = append(b.transactions, transaction)
+}
+
+// name returns the name of an entity
+func (b *transactionBuilder) name(entityID string) string {
+	return b.entities[entityID].Name
+}
+
+// kind returns the minor kind of an entity
+func (b *transactionBuilder) kind(entityID string) string {
+	return b.entities[entityID].Kind.Minor
+}
+
+// lineageOn reports whether an entity was replaced (outgoing) or created (incoming) by a rename, merge or
+// split on the date
+func (b *transactionBuilder) lineageOn(entityID, dateISO string, incoming bool) bool {
+	for _, relType := range lineageRelTypes {
+		for _, rel := range b.related(entityID, relType, incoming) {
+			if rel.StartTime == dateISO {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// succeeds reports whether an entity was renamed, merged or split into another on the date
+func (b *transactionBuilder) succeeds(fromID, toID, dateISO string) bool {
+	for _, relType := range lineageRelTypes {
+		if b.hasRelationOn(fromID, toID, relType, dateISO) {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns how deep a holding is below the president: 0 for ministers, 1 for institutions held by
+// ministers and one more for each level of nested units
+func (b *transactionBuilder) depth(rel models.GraphRelationship) int {
+	depth := 0
+	for ; depth < maxUnitDepth; depth++ {
+		if rel.Name == "AS_MINISTER" {
+			return depth
+		}
+		holder := b.holdingAt(rel.From, rel.StartTime, true)
+		if holder == nil {
+			return depth + 1
+		}
+		rel = *holder
+	}
+	return depth
+}
+
+// holder returns the president a holding belongs to on the date
+func (b *transactionBuilder) holder(rel models.GraphRelationship, dateISO string, endingOn bool) string {
+	if rel.Name == "AS_MINISTER" {
+		return b.name(rel.From)
+	}
+	return b.president(rel.From, dateISO, endingOn)
+}
+
+// presidency adds the presidential terms of the government
+func (b *transactionBuilder) presidency(government models.GraphEntity) {
+	for _, term := range b.related(government.ID, "AS_PRESIDENT", false) {
+		for _, change := range []struct {
+			fileType string
+			date     string
+			rank     int
+		}{{"ADD", term.StartTime, 0}, {"TERMINATE", term.EndTime, 1}} {
+			if change.date == "" {
+				continue
+			}
+			values := map[string]string{
+				"parent":      government.Name,
+				"parent_type": government.Kind.Minor,
+				"child":       b.name(term.To),
+				"child_type":  b.kind(term.To),
+				"rel_type":    term.Name,
+			}
+			if change.fileType == "ADD" {
+				b.attributeColumns(term.To, change.date, values)
+			}
+			b.add(phasePresidency, b.name(term.To), change.date, change.fileType, change.rank, values, term.To)
+		}
+	}
+}
+
+// lineage adds the renames, merges and splits of an entity. Merges are added once for the new entity and
+// splits once for the old one.
+func (b *transactionBuilder) lineage(entity models.GraphEntity) {
+	for _, rel := range b.related(entity.ID, "RENAMED_TO", false) {
+		values := map[string]string{
+			"old":  entity.Name,
+			"new":  b.name(rel.To),
+			"type": entity.Kind.Minor,
+		}
+		b.classification(rel.To, values)
+		b.add(phaseOrgchart, b.president(entity.ID, rel.StartTime, true), rel.StartTime, "RENAME", 10, values, rel.To, entity.ID)
+	}
+
+	merged := make(map[string][]string)
+	for _, rel := range b.related(entity.ID, "MERGED_INTO", true) {
+		merged[rel.StartTime] = append(merged[rel.StartTime], b.name(rel.From))
+	}
+	for _, dateISO := range sortedKeys(merged) {
+		values := map[string]string{
+			"old":  nameList(merged[dateISO]),
+			"new":  entity.Name,
+			"type": entity.Kind.Minor,
+		}
+		if holder := b.holdingAt(entity.ID, dateISO, false); holder != nil && entity.Kind.Minor != "minister" {
+			values["new_parent"] = b.name(holder.From)
+		}
+		b.classification(entity.ID, values)
+		b.add(phaseOrgchart, b.president(entity.ID, dateISO, false), dateISO, "MERGE", 10, values, entity.ID)
+	}
+
+	split := make(map[string][]string)
+	for _, rel := range b.related(entity.ID, "SPLIT_INTO", false) {
+		split[rel.StartTime] = append(split[rel.StartTime], rel.To)
+	}
+	for _, dateISO := range sortedKeys(split) {
+		var names, departments []string
+		appointees := AppointeesTerminate
+		for _, newID := range split[dateISO] {
+			names = append(names, b.name(newID))
+			for _, rel := range b.related(newID, "AS_DEPARTMENT", false) {
+				if rel.StartTime == dateISO && b.endedOn(entity.ID, rel.To, "AS_DEPARTMENT", dateISO) {
+					departments = append(departments, b.name(rel.To)+"="+b.name(newID))
+				}
+			}
+			for _, relType := range appointmentRelTypes(entity.Kind.Minor) {
+				for _, rel := range b.related(newID, relType, false) {
+					if rel.StartTime == dateISO && b.endedOn(entity.ID, rel.To, relType, dateISO) {
+						appointees = b.name(newID)
+					}
+				}
+			}
+		}
+		b.add(phaseOrgchart, b.president(entity.ID, dateISO, true), dateISO, "SPLIT", 10, map[string]string{
+			"old":         entity.Name,
+			"new":         nameList(names),
+			"departments": nameList(departments),
+			"appointees":  appointees,
+		}, split[dateISO]...)
+	}
+}
+
+// endedOn reports whether an entity held another through the given relationship until the date
+func (b *transactionBuilder) endedOn(fromID, toID, relType, dateISO string) bool {
+	for _, rel := range b.related(fromID, relType, false) {
+		if rel.To == toID && rel.EndTime == dateISO {
+			return true
+		}
+	}
+	return false
+}
+
+// holdings adds the transactions creating, moving and terminating the holdings of a minister or institution
+func (b *transactionBuilder) holdings(entity models.GraphEntity) {
+	var holdings []models.GraphRelationship
+	for _, relType := range holdingRelTypes(entity.Kind.Minor) {
+		holdings = append(holdings, b.related(entity.ID, relType, true)...)
+	}
+	sort.SliceStable(holdings, func(i, j int) bool {
+		return holdings[i].StartTime < holdings[j].StartTime
+	})
+
+	for _, holding := range holdings {
+		// Entities created by a rename, merge or split are added by it
+		if b.lineageOn(entity.ID, holding.StartTime, true) {
+			continue
+		}
+
+		var previous *models.GraphRelationship
+		for i, other := range holdings {
+			if other.ID != holding.ID && other.EndTime == holding.StartTime && !b.applied[other.ID] {
+				previous = &holdings[i]
+				break
+			}
+		}
+		if previous != nil {
+			b.applied[previous.ID] = true
+		}
+
+		depth := b.depth(holding)
+		switch {
+		case previous != nil && (previous.From == holding.From || b.succeeds(previous.From, holding.From, holding.StartTime)):
+			// The holding was continued, or carried over by a rename, merge or split of the parent
+		case previous != nil:
+			values := map[string]string{
+				"old_parent":         b.name(previous.From),
+				"new_parent":         b.name(holding.From),
+				"child":              entity.Name,
+				"type":               entity.Kind.Minor,
+				"old_president_name": b.holder(*previous, holding.StartTime, true),
+				"new_president_name": b.holder(holding, holding.StartTime, false),
+			}
+			if holding.Name == "AS_MINISTER" {
+				values["appointees"] = b.movedAppointees(entity.ID, holding.StartTime)
+			} else {
+				values["new_parent_type"] = b.kind(holding.From)
+			}
+			b.add(phaseOrgchart, values["new_president_name"], holding.StartTime, "MOVE", 20+depth, values, entity.ID)
+		default:
+			values := map[string]string{
+				"parent":      b.name(holding.From),
+				"parent_type": b.kind(holding.From),
+				"child":       entity.Name,
+				"child_type":  entity.Kind.Minor,
+				"rel_type":    holding.Name,
+			}
+			b.classification(entity.ID, values)
+			b.add(phaseOrgchart, b.holder(holding, holding.StartTime, false), holding.StartTime, "ADD", depth, values, entity.ID)
+		}
+	}
+
+	for _, holding := range holdings {
+		// Entities replaced by a rename, merge or split are ended by it
+		if holding.EndTime == "" || b.applied[holding.ID] || b.lineageOn(entity.ID, holding.EndTime, false) {
+			continue
+		}
+		if holding.Name == "AS_MINISTER" {
+			b.terminated[entity.ID+"|"+holding.EndTime] = true
+		}
+		b.add(phaseOrgchart, b.holder(holding, holding.EndTime, true), holding.EndTime, "TERMINATE", 40-b.depth(holding), map[string]string{
+			"parent":      b.name(holding.From),
+			"parent_type": b.kind(holding.From),
+			"child":       entity.Name,
+			"child_type":  entity.Kind.Minor,
+			"rel_type":    holding.Name,
+		}, entity.ID)
+	}
+}
+
+// movedAppointees returns the appointees option of a minister moved to another president on the date:
+// terminate when every appointment of the minister ended that day, keep otherwise
+func (b *transactionBuilder) movedAppointees(ministerID, dateISO string) string {
+	var ended []string
+	for _, relType := range appointmentRelTypes("minister") {
+		for _, rel := range b.related(ministerID, relType, false) {
+			if rel.StartTime >= dateISO || (rel.EndTime != "" && rel.EndTime < dateISO) {
+				continue
+			}
+			if rel.EndTime != dateISO {
+				return AppointeesKeep
+			}
+			ended = append(ended, rel.ID)
+		}
+	}
+	if len(ended) == 0 {
+		return AppointeesKeep
+	}
+	for _, id := range ended {
+		b.applied[id] = true
+	}
+	return AppointeesTerminate
+}
+
+// appointments adds the transactions appointing, moving and removing a person
+func (b *transactionBuilder) appointments(person models.GraphEntity) {
+	var appointments []models.GraphRelationship
+	appointeeTypes := appointeeRelTypes()
+	for _, rel := range b.incoming[person.ID] {
+		if appointeeTypes[rel.Name] {
+			appointments = append(appointments, rel)
+		}
+	}
+
+	for _, appointment := range appointments {
+		var previous *models.GraphRelationship
+		for i, other := range appointments {
+			if other.ID != appointment.ID && other.EndTime == appointment.StartTime && !b.applied[other.ID] {
+				previous = &appointments[i]
+				break
+			}
+		}
+		if previous != nil {
+			b.applied[previous.ID] = true
+		}
+
+		switch {
+		case previous != nil && ((previous.From == appointment.From && previous.Name == appointment.Name) ||
+			b.succeeds(previous.From, appointment.From, appointment.StartTime)):
+			// The appointment was continued, or carried over by a rename, merge or split of the parent
+		case previous != nil:
+			president := b.president(appointment.From, appointment.StartTime, false)
+			b.add(phasePeople, president, appointment.StartTime, "MOVE", 1, map[string]string{
+				"old_parent":         b.name(previous.From),
+				"old_parent_type":    b.kind(previous.From),
+				"old_president_name": b.president(previous.From, appointment.StartTime, true),
+				"new_parent":         b.name(appointment.From),
+				"new_parent_type":    b.kind(appointment.From),
+				"new_president_name": president,
+				"child":              person.Name,
+				"type":               person.Kind.Minor,
+				"role":               roleName(appointment.Name),
+				"old_role":           roleName(previous.Name),
+			}, person.ID)
+		default:
+			values := map[string]string{
+				"parent":      b.name(appointment.From),
+				"parent_type": b.kind(appointment.From),
+				"child":       person.Name,
+				"child_type":  person.Kind.Minor,
+				"rel_type":    appointment.Name,
+			}
+			b.attributeColumns(person.ID, appointment.StartTime, values)
+			b.add(phasePeople, b.president(appointment.From, appointment.StartTime, false), appointment.StartTime, "ADD", 0, values, person.ID)
+		}
+	}
+
+	for _, appointment := range appointments {
+		// Appointments are ended with their minister, or carried over or cut by a rename, merge or split
+		if appointment.EndTime == "" || b.applied[appointment.ID] || b.terminated[appointment.From+"|"+appointment.EndTime] ||
+			b.lineageOn(appointment.From, appointment.EndTime, false) {
+			continue
+		}
+		b.add(phasePeople, b.president(appointment.From, appointment.EndTime, true), appointment.EndTime, "TERMINATE", 2, map[string]string{
+			"parent":      b.name(appointment.From),
+			"parent_type": b.kind(appointment.From),
+			"child":       person.Name,
+			"child_type":  person.Kind.Minor,
+			"rel_type":    appointment.Name,
+		}, person.ID)
+	}
+}
+
+// classification sets the category and gazette position columns of the transaction creating a minister. A
+// minister is classified once, when it is created.
+func (b *transactionBuilder) classification(ministerID string, values map[string]string) {
+	minister := b.entities[ministerID]
+	if minister.Kind.Minor != "minister" {
+		return
+	}
+	for _, name := range []string{MinisterCategoryAttribute, MinisterGazettePositionAttribute} {
+		var first *models.TimeBasedValue
+		for i, value := range minister.Attributes[name] {
+			if first == nil || value.StartTime < first.StartTime {
+				first = &minister.Attributes[name][i]
+			}
+		}
+		if first != nil && first.Value != nil {
+			values[name] = fmt.Sprint(first.Value)
+		}
+	}
+}
+
+// attributeColumns sets the attribute columns of the ADD row creating a person to the values in effect on the
+// date. Values with an end date cannot be given as columns and are left to attributes.
+func (b *transactionBuilder) attributeColumns(personID, dateISO string, values map[string]string) {
+	person := b.entities[personID]
+	if person.Created != dateISO || b.attributed[personID] {
+		return
+	}
+	b.attributed[personID] = true
+	for name, attributeValues := range person.Attributes {
+		value, ok := valueAsOf(attributeValues, dateISO)
+		if !ok || value.EndTime != "" || value.Value == nil {
+			continue
+		}
+		values[attributeColumn(name)] = fmt.Sprint(value.Value)
+		b.attributed[attributeValueKey(personID, name, value)] = true
+	}
+}
+
+// attributes adds an ATTRIBUTE transaction for each attribute value of a person not given as a column of
+// the ADD row creating the person. Values starting before the person was created are set from its creation.
+func (b *transactionBuilder) attributes(person models.GraphEntity) {
+	var names []string
+	for name := range person.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range person.Attributes[name] {
+			if b.attributed[attributeValueKey(person.ID, name, value)] || value.Value == nil {
+				continue
+			}
+			dateISO := maxString(value.StartTime, person.Created)
+			if value.EndTime != "" && value.EndTime <= dateISO {
+				continue
+			}
+			values := map[string]string{
+				"child":     person.Name,
+				"attribute": name,
+				"value":     fmt.Sprint(value.Value),
+			}
+			if value.EndTime != "" {
+				values["end_date"] = value.EndTime[:10]
+			}
+			b.add(phasePeople, b.directoryPresident(b.government.ID, dateISO), dateISO, "ATTRIBUTE", 3, values, person.ID)
+		}
+	}
+}
+
+// attributeColumn returns the ADD column of an attribute: the known person attributes have their own
+// columns, other attributes are prefixed with "attr_"
+func attributeColumn(name string) string {
+	if isPersonAttribute(name) {
+		return name
+	}
+	return attributeColumnPrefix + name
+}
+
+// attributeValueKey identifies an attribute value of a person
+func attributeValueKey(personID, name string, value models.TimeBasedValue) string {
+	return personID + "|" + name + "|" + value.StartTime
+}
+
+// documents adds the transactions adding a document to each entity it belongs to, and linking it to the
+// documents it amends, corrects or supersedes
+func (b *transactionBuilder) documents(document models.GraphEntity) {
+	for _, rel := range b.related(document.ID, "AS_DOCUMENT", true) {
+		// A parent created on the date is only there once the changes of the date are loaded
+		parent := b.entities[rel.From]
+		phase := phaseDocuments
+		if parent.Kind.Minor != "government" && parent.Created >= rel.StartTime {
+			phase = phaseDocumentLinks
+		}
+
+		values := map[string]string{
+			"child":       document.Name,
+			"child_type":  document.Kind.Minor,
+			"parent":      parent.Name,
+			"parent_type": parent.Kind.Minor,
+		}
+		for _, key := range documentMetadataColumns {
+			if value, ok := document.Metadata[key]; ok && value != nil {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+		b.add(phase, b.directoryPresident(rel.From, rel.StartTime), rel.StartTime, "ADD", 0, values)
+	}
+
+	for _, fileType := range transactionFileTypes {
+		relType, ok := documentLifecycleRelTypes[fileType]
+		if !ok {
+			continue
+		}
+		for _, rel := range b.related(document.ID, relType, false) {
+			// Both documents have to be loaded first, the link keeps its own date
+			documentISO, targetISO := b.documentLoaded(rel.From), b.documentLoaded(rel.To)
+			if documentISO == "" || targetISO == "" {
+				continue
+			}
+			loadISO := maxString(rel.StartTime, maxString(documentISO, targetISO))
+			values := map[string]string{
+				"document": document.Name,
+				"target":   b.name(rel.To),
+			}
+			b.add(phaseDocumentLinks, b.directoryPresident(b.government.ID, loadISO), loadISO, fileType, 1, values)
+			values["date"] = rel.StartTime[:10]
+		}
+	}
+}
+
+// documentLoaded returns the date a document is first added by the transactions, empty when it is not
+func (b *transactionBuilder) documentLoaded(documentID string) string {
+	loaded := ""
+	for _, rel := range b.related(documentID, "AS_DOCUMENT", true) {
+		if loaded == "" || rel.StartTime < loaded {
+			loaded = rel.StartTime
+		}
+	}
+	return loaded
+}
+
+// directoryPresident returns the president directory of a row: the president holding its parent, or in
+// office, on the date
+func (b *transactionBuilder) directoryPresident(parentID, dateISO string) string {
+	if president := b.president(parentID, dateISO, false); president != "" {
+		return president
+	}
+	if president := b.president(b.government.ID, dateISO, false); president != "" {
+		return president
+	}
+	return b.government.Name
+}
+
+// directories groups the transactions into their directories and numbers them
+func (b *transactionBuilder) directories() []models.TransactionDirectory {
+	type directoryKey struct {
+		date      string
+		phase     int
+		president string
+	}
+	grouped := make(map[directoryKey][]pendingTransaction)
+	var keys []directoryKey
+	for _, transaction := range b.transactions {
+		key := directoryKey{date: transaction.date, phase: transaction.phase, president: transaction.president}
+		if grouped[key] == nil {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], transaction)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		if keys[i].phase != keys[j].phase {
+			return keys[i].phase < keys[j].phase
+		}
+		return keys[i].president < keys[j].president
+	})
+
+	var directories []models.TransactionDirectory
+	for _, key := range keys {
+		transactions := grouped[key]
+		sort.SliceStable(transactions, func(i, j int) bool {
+			return transactions[i].rank < transactions[j].rank
+		})
+
+		directory := models.TransactionDirectory{
+			Path:        path.Join("orgchart", key.president, key.date[:10]),
+			ProcessType: "organisation",
+			Date:        key.date,
+		}
+		switch key.phase {
+		case phaseDocuments:
+			directory.Path = path.Join("documents", key.president, key.date[:10])
+			directory.ProcessType = "document"
+		case phaseDocumentLinks:
+			directory.Path = path.Join("documents", key.president, key.date[:10], "links")
+			directory.ProcessType = "document"
+		case phasePresidency:
+			directory.Path = path.Join("people", key.president, key.date[:10], "presidency")
+			directory.ProcessType = "person"
+		case phasePeople:
+			directory.Path = path.Join("people", key.president, key.date[:10])
+			directory.ProcessType = "person"
+		}
+
+		prefix := directoryGazette(transactions)
+		files := make(map[string]*models.TransactionFile)
+		for i, transaction := range transactions {
+			file := files[transaction.fileType]
+			if file == nil {
+				file = &models.TransactionFile{
+					Type:    transaction.fileType,
+					Name:    transaction.fileType + ".csv",
+					Columns: append(transactionColumns(key.phase, transaction.fileType), optionalColumns(transactions, transaction.fileType)...),
+				}
+				// Document files are recognised by their suffix, e.g. documents_ADD.csv
+				if isDocumentPhase(key.phase) {
+					file.Name = "documents_" + file.Name
+				}
+				files[transaction.fileType] = file
+			}
+			transaction.values["transaction_id"] = fmt.Sprintf("%s_tr_%02d", prefix, i+1)
+			row := make([]string, len(file.Columns))
+			for j, column := range file.Columns {
+				row[j] = transaction.values[column]
+			}
+			file.Rows = append(file.Rows, row)
+		}
+		for _, fileType := range transactionFileTypes {
+			if file := files[fileType]; file != nil {
+				directory.Files = append(directory.Files, *file)
+			}
+		}
+		directories = append(directories, directory)
+	}
+	return directories
+}
+
+// directoryGazette returns the gazette most transactions of a directory were sourced from (the earliest on a
+// tie), or the date when none was sourced
+func directoryGazette(transactions []pendingTransaction) string {
+	counts := make(map[string]int)
+	for _, transaction := range transactions {
+		seen := make(map[string]bool)
+		for _, gazette := range transaction.gazettes {
+			if !seen[gazette] {
+				seen[gazette] = true
+				counts[gazette]++
+			}
+		}
+	}
+	best := ""
+	for gazette, count := range counts {
+		if best == "" || count > counts[best] || (count == counts[best] && gazette < best) {
+			best = gazette
+		}
+	}
+	if best == "" {
+		return transactions[0].date[:10]
+	}
+	return best
+}
+
+// roleName returns the name of the appointment role of a relationship type
+func roleName(relType string) string {
+	role, err := GetAppointmentRoleByRelType(relType)
+	if err != nil {
+		return ""
+	}
+	return role.Name
+}
+
+// nameList formats names as a bracketed, semicolon separated list, e.g. "[Minister A;Minister B]"
+func nameList(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ";") + "]"
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(values map[string][]string) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"orgchart_nexoan/api"
+)
+
+// command is a subcommand of the tool with its own flags, e.g. "orgchart snapshot -date 2022-05-14"
+type command struct {
+	description string
+	run         func(args []string) error
+}
+
+// commands lists the subcommands by name. Without a subcommand the tool processes transactions.
+var commands = map[string]command{
+	"snapshot":     {"Export the org chart as it stood on a date (JSON or CSV)", runSnapshot},
+	"diff":         {"List the changes of the org chart between two dates (changelog or JSON)", runDiff},
+	"render":       {"Draw the org chart of a date or period as a GraphViz DOT or Mermaid chart", runRender},
+	"export":       {"Export the entity graph as JSON, a Cypher script or neo4j-admin CSV files", runExport},
+	"import":       {"Load an exported entity graph into an empty backend", runImport},
+	"lineage":      {"List the renames, merges and splits of a minister or institution", runLineage},
+	"person":       {"List the appointments and presidential terms of a person", runPerson},
+	"custody":      {"List the ministers that held a department, or the departments that changed hands most", runCustody},
+	"stats":        {"Compute statistics per president and year (CSV or JSON)", runStats},
+	"transactions": {"Write the graph back as gazette and transaction CSVs that rebuild it, with a load script", runTransactions},
+	"serve":        {"Serve snapshots, ministers, timelines, lineages and diffs as JSON over HTTP", runServe},
+}
+
+// commandNames returns the subcommand names in a stable order
+func commandNames() []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// endpointFlags holds the API endpoint flags shared by every command
+type endpointFlags struct {
+	update *string
+	query  *string
+}
+
+// addEndpointFlags registers the API endpoint flags on a command's flag set
+func addEndpointFlags(flags *flag.FlagSet) *endpointFlags {
+	return &endpointFlags{
+		update: flags.String("update_endpoint", "http://localhost:8080/entities", "Endpoint for the Update API"),
+		query:  flags.String("query_endpoint", "http://localhost:8081/v1/entities", "Endpoint for the Query API"),
+	}
+}
+
+// client creates an API client for the configured endpoints
+func (e *endpointFlags) client() *api.Client {
+	return api.NewClient(*e.update, *e.query)
+}
+
+// parseDateFlag parses a YYYY-MM-DD flag value into RFC3339
+func parseDateFlag(name, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("-%s is required (YYYY-MM-DD)", name)
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid -%s '%s', expected YYYY-MM-DD", name, value)
+	}
+	return date.Format(time.RFC3339), nil
+}
+
+// openOutput opens the file given with -output, or standard output when no file is given
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	return file, nil
+}
+
+// nopCloser keeps standard output open when the command finishes
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+)
+
+// runCustody lists the ministers that held a department, or the departments that changed hands most
+func runCustody(args []string) error {
+	flags := flag.NewFlagSet("custody", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	name := flags.String("name", "", "Name of the department or other institution")
+	top := flags.Int("top", 0, "Instead of -name, report the N departments that changed hands most")
+	format := flags.String("format", "text", "Output format: 'text' or 'json'")
+	output := flags.String("output", "", "File to write the history to (default: standard output)")
+	flags.Parse(args)
+
+	if (strings.TrimSpace(*name) == "") == (*top <= 0) {
+		return fmt.Errorf("either -name or -top is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid -format '%s', must be 'text' or 'json'", *format)
+	}
+
+	client := endpoints.client()
+	var histories []models.DepartmentCustody
+	var err error
+	if *top > 0 {
+		histories, err = client.GetMostHandedOver(*top)
+	} else {
+		histories, err = client.GetDepartmentCustodyByName(strings.TrimSpace(*name))
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	switch {
+	case *format == "json":
+		return export.WriteCustodyJSON(out, histories)
+	case *top > 0:
+		return export.WriteHandoverReport(out, histories)
+	default:
+		return export.WriteCustodyText(out, histories)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"orgchart_nexoan/export"
+)
+
+// runDiff lists the changes of the org chart between two dates
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	from := flags.String("from", "", "Start date, YYYY-MM-DD (required)")
+	to := flags.String("to", "", "End date, YYYY-MM-DD (required)")
+	format := flags.String("format", "text", "Output format: 'text' (changelog) or 'json'")
+	output := flags.String("output", "", "File to write the changes to (default: standard output)")
+	flags.Parse(args)
+
+	fromISO, err := parseDateFlag("from", *from)
+	if err != nil {
+		return err
+	}
+	toISO, err := parseDateFlag("to", *to)
+	if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid -format '%s', must be 'text' or 'json'", *format)
+	}
+
+	diff, err := endpoints.client().GetDiff(fromISO, toISO)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "json" {
+		return export.WriteDiffJSON(out, diff)
+	}
+	return export.WriteDiffText(out, diff)
+}
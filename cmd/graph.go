@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+)
+
+// graphEntitiesFile and graphRelationshipsFile are the files of a CSV graph export, written to and read from one directory
+const (
+	graphEntitiesFile      = "entities.csv"
+	graphRelationshipsFile = "relationships.csv"
+)
+
+// runExport writes the entity graph to portable files
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	format := flags.String("format", "json", "Output format: 'json' (read by import), 'cypher' (CREATE script) or 'csv' (neo4j-admin import files)")
+	output := flags.String("output", "", "File to write the graph to (default: standard output); a directory for 'csv'")
+	flags.Parse(args)
+
+	if *format != "json" && *format != "cypher" && *format != "csv" {
+		return fmt.Errorf("invalid -format '%s', must be 'json', 'cypher' or 'csv'", *format)
+	}
+	if *format == "csv" && *output == "" {
+		return fmt.Errorf("-output must name a directory for the 'csv' format")
+	}
+
+	graph, err := endpoints.client().GetGraph()
+	if err != nil {
+		return err
+	}
+
+	if *format == "csv" {
+		if err := os.MkdirAll(*output, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		entities, err := os.Create(filepath.Join(*output, graphEntitiesFile))
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer entities.Close()
+		relationships, err := os.Create(filepath.Join(*output, graphRelationshipsFile))
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer relationships.Close()
+		return export.WriteGraphCSV(entities, relationships, graph)
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "cypher" {
+		return export.WriteGraphCypher(out, graph)
+	}
+	return export.WriteGraphJSON(out, graph)
+}
+
+// runImport loads a graph written by the export command into an empty backend
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	input := flags.String("input", "", "JSON graph file, or directory of a CSV graph export (required)")
+	flags.Parse(args)
+
+	if *input == "" {
+		return fmt.Errorf("-input is required")
+	}
+	graph, err := readGraph(*input)
+	if err != nil {
+		return err
+	}
+	return endpoints.client().ImportGraph(graph)
+}
+
+// readGraph reads a graph export: a JSON file or the directory of a CSV export
+func readGraph(path string) (*models.Graph, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	if info.IsDir() {
+		entities, err := os.Open(filepath.Join(path, graphEntitiesFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open input: %w", err)
+		}
+		defer entities.Close()
+		relationships, err := os.Open(filepath.Join(path, graphRelationshipsFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open input: %w", err)
+		}
+		defer relationships.Close()
+		return export.ReadGraphCSV(entities, relationships)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	defer file.Close()
+	return export.ReadGraphJSON(file)
+}
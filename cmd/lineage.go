@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"orgchart_nexoan/export"
+)
+
+// runLineage lists the renames, merges and splits a minister or institution went through
+func runLineage(args []string) error {
+	flags := flag.NewFlagSet("lineage", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	name := flags.String("name", "", "Name of the minister or institution (required)")
+	format := flags.String("format", "text", "Output format: 'text' or 'json'")
+	output := flags.String("output", "", "File to write the lineage to (default: standard output)")
+	flags.Parse(args)
+
+	if strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid -format '%s', must be 'text' or 'json'", *format)
+	}
+
+	lineages, err := endpoints.client().GetLineageByName(strings.TrimSpace(*name))
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "json" {
+		return export.WriteLineageJSON(out, lineages)
+	}
+	return export.WriteLineageText(out, lineages)
+}
@@ -3,7 +3,33 @@
 //
 // Usage:
 //
-//	go run cmd/main.go -data <data_directory> [options]
+//	go run ./cmd -data <data_directory> [options]
+//	go run ./cmd <command> [options]
+//
+// Commands read the org chart through the Query API (import writes through the Update API):
+//
+//	snapshot -date YYYY-MM-DD [-format json|csv] [-output file]
+//	      Export the org chart as it stood on a date
+//	diff -from YYYY-MM-DD -to YYYY-MM-DD [-format text|json] [-output file]
+//	      List the changes of the org chart between two dates
+//	render (-date YYYY-MM-DD | -from YYYY-MM-DD -to YYYY-MM-DD) [-format dot|mermaid] [-president name | -minister name] [-appointees] [-sources]
+//	      Draw the org chart as a GraphViz DOT or Mermaid chart
+//	export [-format json|cypher|csv] [-output file or directory]
+//	      Export the entity graph to portable files
+//	import -input file or directory
+//	      Load an exported graph into an empty backend through the Update API
+//	lineage -name "<minister or institution>" [-format text|json] [-output file]
+//	      List the ancestry and descendants of an entity across renames, merges and splits
+//	person -name "<citizen>" [-format text|json] [-people file] [-output file]
+//	      List the career of a person across presidencies and how each appointment ended
+//	custody (-name "<department>" | -top N) [-format text|json] [-output file]
+//	      List the ministers that held a department, or the departments that changed hands most
+//	stats [-input graph export] [-format csv|json] [-output file]
+//	      Compute statistics per president and year from the Query API or a graph export
+//	serve [-addr host:port] [-cache duration]
+//	      Serve snapshots, ministers, person timelines, lineages and diffs as JSON over HTTP
+//	transactions -output directory [-input graph export] [-date YYYY-MM-DD]
+//	      Write the graph back as gazette and ADD, MOVE, RENAME, MERGE, SPLIT and TERMINATE CSVs with a load script
 //
 // Required flags:
 //
@@ -20,23 +46,27 @@
 //	      Endpoint for the Update API (default "http://localhost:8080/entities")
 //	-query_endpoint string
 //	      Endpoint for the Query API (default "http://localhost:8081/v1/entities")
+//	-people string
+//	      Path to a person registry (JSON or CSV) of canonical person IDs and name variants
+//	-presidents string
+//	      Path to a president alias file (JSON or CSV) adding to the built-in aliases such as ":RW"
 //
 // Examples:
 //
 //  0. Get help:
-//     go run cmd/main.go --help
+//     go run ./cmd --help
 //
 //  1. Process organisation data with default settings:
-//     go run cmd/main.go -data /path/to/data/directory
+//     go run ./cmd -data /path/to/data/directory
 //
 //  2. Process person data:
-//     go run cmd/main.go -data /path/to/data/directory -type person
+//     go run ./cmd -data /path/to/data/directory -type person
 //
 //  3. Initialize database and process organisation data:
-//     go run cmd/main.go -data /path/to/data/directory -init
+//     go run ./cmd -data /path/to/data/directory -init
 //
 //  4. Use custom API endpoints:
-//     go run cmd/main.go -data /path/to/data/directory -update_endpoint http://custom:8080/entities -query_endpoint http://custom:8081/v1/entities
+//     go run ./cmd -data /path/to/data/directory -update_endpoint http://custom:8080/entities -query_endpoint http://custom:8081/v1/entities
 //
 // Process Types:
 //   - organisation: Processes minister and department entities
@@ -54,17 +84,34 @@ import (
 )
 
 func main() {
+	// Subcommands have their own flags
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatalf("%s failed: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
 	// Define command line flags with detailed descriptions
 	dataDir := flag.String("data", "", "Path to the data directory containing transactions (required)")
 	initDB := flag.Bool("init", false, "Initialize the database with government node before processing transactions")
 	updateEndpoint := flag.String("update_endpoint", "http://localhost:8080/entities", "Endpoint for the Update API (default: http://localhost:8080/entities)")
 	queryEndpoint := flag.String("query_endpoint", "http://localhost:8081/v1/entities", "Endpoint for the Query API (default: http://localhost:8081/v1/entities)")
 	processType := flag.String("type", "organisation", "Type of data to process: 'organisation' or 'person' or 'document' (default: organisation)")
+	peopleRegistry := flag.String("people", "", "Path to a person registry (JSON or CSV) of canonical person IDs and name variants (optional)")
+	presidentAliases := flag.String("presidents", "", "Path to a president alias file (JSON or CSV) adding to the built-in aliases such as ':RW' (optional)")
 
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Process organisation chart transactions from a specified data directory.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands (run '%s <command> -h' for their flags):\n", os.Args[0])
+		for _, name := range commandNames() {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].description)
+		}
+		fmt.Fprintf(os.Stderr, "\n")
 		fmt.Fprintf(os.Stderr, "Required flags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -108,6 +155,24 @@ func main() {
 	// Create API client with configurable endpoints
 	client := api.NewClient(*updateEndpoint, *queryEndpoint)
 
+	// Load the person registry used to resolve name variants
+	if *peopleRegistry != "" {
+		registry, err := api.LoadPersonRegistry(*peopleRegistry)
+		if err != nil {
+			log.Fatalf("Failed to load person registry: %v", err)
+		}
+		client.SetPersonRegistry(registry)
+	}
+
+	// Load the president aliases used in the CSVs
+	if *presidentAliases != "" {
+		aliases, err := api.LoadPresidentAliases(*presidentAliases)
+		if err != nil {
+			log.Fatalf("Failed to load president aliases: %v", err)
+		}
+		client.SetPresidentAliases(aliases)
+	}
+
 	// Initialize database if requested
 	if *initDB {
 		fmt.Println("Initializing database with government node...")
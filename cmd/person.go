@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+)
+
+// runPerson lists the career of a person across presidencies
+func runPerson(args []string) error {
+	flags := flag.NewFlagSet("person", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	name := flags.String("name", "", "Name of the person (required)")
+	format := flags.String("format", "text", "Output format: 'text' or 'json'")
+	output := flags.String("output", "", "File to write the timeline to (default: standard output)")
+	people := flags.String("people", "", "Path to a person registry (JSON or CSV) used to resolve the name")
+	flags.Parse(args)
+
+	if strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid -format '%s', must be 'text' or 'json'", *format)
+	}
+
+	client := endpoints.client()
+	if *people != "" {
+		registry, err := api.LoadPersonRegistry(*people)
+		if err != nil {
+			return err
+		}
+		client.SetPersonRegistry(registry)
+	}
+
+	timeline, err := client.GetPersonTimelineByName(strings.TrimSpace(*name))
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "json" {
+		return export.WritePersonTimelineJSON(out, timeline)
+	}
+	return export.WritePersonTimelineText(out, timeline)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"orgchart_nexoan/export"
+)
+
+// runRender draws the org chart of a date or period as a GraphViz DOT or Mermaid chart
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	date := flags.String("date", "", "Date to draw, YYYY-MM-DD (or use -from and -to)")
+	from := flags.String("from", "", "Start of the period to draw, YYYY-MM-DD")
+	to := flags.String("to", "", "End of the period to draw, YYYY-MM-DD; relationships ended within the period are drawn dashed")
+	format := flags.String("format", "dot", "Output format: 'dot' (GraphViz) or 'mermaid'")
+	president := flags.String("president", "", "Only draw the ministers of this president")
+	minister := flags.String("minister", "", "Only draw this minister")
+	appointees := flags.Bool("appointees", false, "Draw the people appointed to ministers and institutions")
+	sources := flags.Bool("sources", false, "Draw the gazettes each relationship was taken from")
+	output := flags.String("output", "", "File to write the chart to (default: standard output)")
+	flags.Parse(args)
+
+	if *format != "dot" && *format != "mermaid" {
+		return fmt.Errorf("invalid -format '%s', must be 'dot' or 'mermaid'", *format)
+	}
+	if *president != "" && *minister != "" {
+		return fmt.Errorf("-president and -minister cannot be combined")
+	}
+
+	var fromISO, toISO string
+	var err error
+	if *date != "" {
+		if *from != "" || *to != "" {
+			return fmt.Errorf("-date cannot be combined with -from and -to")
+		}
+		fromISO, err = parseDateFlag("date", *date)
+		if err != nil {
+			return err
+		}
+		toISO = fromISO
+	} else {
+		fromISO, err = parseDateFlag("from", *from)
+		if err != nil {
+			return fmt.Errorf("%w (or give -date)", err)
+		}
+		toISO, err = parseDateFlag("to", *to)
+		if err != nil {
+			return err
+		}
+	}
+
+	snapshot, err := endpoints.client().GetSnapshotRange(fromISO, toISO)
+	if err != nil {
+		return err
+	}
+
+	root := snapshot.Government
+	if *president != "" {
+		root, err = export.SelectSubtree(snapshot, "citizen", *president)
+	} else if *minister != "" {
+		root, err = export.SelectSubtree(snapshot, "minister", *minister)
+	}
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	options := export.RenderOptions{Appointees: *appointees, Sources: *sources}
+	if *format == "mermaid" {
+		return export.WriteMermaid(out, snapshot, root, options)
+	}
+	return export.WriteDOT(out, snapshot, root, options)
+}
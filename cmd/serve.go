@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"orgchart_nexoan/server"
+)
+
+// runServe serves the org chart views as JSON over HTTP
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	addr := flags.String("addr", "localhost:8090", "Address to listen on")
+	cache := flags.Duration("cache", 5*time.Minute, "How long responses are cached, e.g. 30s or 10m (0 disables caching)")
+	flags.Parse(args)
+
+	if *cache < 0 {
+		return fmt.Errorf("invalid -cache %s, must not be negative", *cache)
+	}
+
+	log.Printf("Serving the org chart on http://%s (cache %s)", *addr, *cache)
+	return http.ListenAndServe(*addr, server.New(endpoints.client(), *cache))
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"orgchart_nexoan/export"
+)
+
+// runSnapshot exports the org chart as it stood on a date
+func runSnapshot(args []string) error {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	date := flags.String("date", "", "Date of the snapshot, YYYY-MM-DD (required)")
+	format := flags.String("format", "json", "Output format: 'json' (nested tree) or 'csv' (one row per entity)")
+	output := flags.String("output", "", "File to write the snapshot to (default: standard output)")
+	flags.Parse(args)
+
+	dateISO, err := parseDateFlag("date", *date)
+	if err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("invalid -format '%s', must be 'json' or 'csv'", *format)
+	}
+
+	snapshot, err := endpoints.client().GetSnapshot(dateISO)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "csv" {
+		return export.WriteSnapshotCSV(out, snapshot)
+	}
+	return export.WriteSnapshotJSON(out, snapshot)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+)
+
+// runStats computes statistics per president and year, from the Query API or a graph export
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	input := flags.String("input", "", "Graph export (JSON file or CSV directory) to compute from instead of the Query API")
+	format := flags.String("format", "csv", "Output format: 'csv' or 'json'")
+	output := flags.String("output", "", "File to write the statistics to (default: standard output)")
+	flags.Parse(args)
+
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("invalid -format '%s', must be 'csv' or 'json'", *format)
+	}
+
+	var stats []models.PresidencyStats
+	if *input != "" {
+		graph, err := readGraph(*input)
+		if err != nil {
+			return err
+		}
+		stats = api.ComputeStats(graph)
+	} else {
+		var err error
+		stats, err = endpoints.client().GetStats()
+		if err != nil {
+			return err
+		}
+	}
+
+	out, err := openOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if *format == "json" {
+		return export.WriteStatsJSON(out, stats)
+	}
+	return export.WriteStatsCSV(out, stats)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+)
+
+// loadScriptFile is the script written next to the transaction directories
+const loadScriptFile = "load.sh"
+
+// runTransactions writes the transaction CSVs rebuilding the graph, from the Query API or a graph export
+func runTransactions(args []string) error {
+	flags := flag.NewFlagSet("transactions", flag.ExitOnError)
+	endpoints := addEndpointFlags(flags)
+	input := flags.String("input", "", "Graph export (JSON file or CSV directory) to read instead of the Query API")
+	date := flags.String("date", "", "Only write the changes up to this date, YYYY-MM-DD (default: every change)")
+	output := flags.String("output", "", "Empty or new directory to write the transactions to (required)")
+	flags.Parse(args)
+
+	if *output == "" {
+		return fmt.Errorf("-output is required")
+	}
+	untilISO := ""
+	if *date != "" {
+		var err error
+		untilISO, err = parseDateFlag("date", *date)
+		if err != nil {
+			return err
+		}
+	}
+	if entries, err := os.ReadDir(*output); err == nil && len(entries) > 0 {
+		return fmt.Errorf("output directory %s is not empty", *output)
+	}
+
+	var graph *models.Graph
+	var err error
+	if *input != "" {
+		graph, err = readGraph(*input)
+	} else {
+		graph, err = endpoints.client().GetGraph()
+	}
+	if err != nil {
+		return err
+	}
+
+	directories := api.BuildTransactions(graph, untilISO)
+	count := 0
+	for _, directory := range directories {
+		dir := filepath.Join(*output, filepath.FromSlash(directory.Path))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		for _, file := range directory.Files {
+			if err := writeTransactionFile(filepath.Join(dir, file.Name), file); err != nil {
+				return err
+			}
+			count += len(file.Rows)
+		}
+	}
+
+	if err := os.MkdirAll(*output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	script, err := os.OpenFile(filepath.Join(*output, loadScriptFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer script.Close()
+	if err := export.WriteLoadScript(script, directories); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d transactions in %d directories to %s\n", count, len(directories), *output)
+	return nil
+}
+
+// writeTransactionFile writes one transaction CSV
+func writeTransactionFile(path string, file models.TransactionFile) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer out.Close()
+	return export.WriteTransactionCSV(out, file)
+}
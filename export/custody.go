@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// WriteCustodyJSON writes custody histories as indented JSON
+func WriteCustodyJSON(w io.Writer, histories []models.DepartmentCustody) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(histories); err != nil {
+		return fmt.Errorf("failed to write custody JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteCustodyText writes the ministers that held each department, oldest first
+func WriteCustodyText(w io.Writer, histories []models.DepartmentCustody) error {
+	var b strings.Builder
+	for i, history := range histories {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s (%s %s), %d handovers\n", history.Name, history.Type, history.ID, history.Handovers)
+		if len(history.Custodians) == 0 {
+			b.WriteString("  never held by a minister\n")
+		}
+		for _, custody := range history.Custodians {
+			fmt.Fprintf(&b, "  %s\n", DescribeCustody(custody))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHandoverReport writes one line per department with the number of times it changed hands
+func WriteHandoverReport(w io.Writer, histories []models.DepartmentCustody) error {
+	var b strings.Builder
+	b.WriteString("Departments that changed hands most\n")
+	if len(histories) == 0 {
+		b.WriteString("\nNo department changed hands\n")
+	}
+	for i, history := range histories {
+		var ministers []string
+		for _, custody := range history.Custodians {
+			ministers = append(ministers, custody.Ministers[len(custody.Ministers)-1])
+		}
+		fmt.Fprintf(&b, "%3d. %s: %d handovers (%s)\n", i+1, history.Name, history.Handovers, strings.Join(ministers, " > "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DescribeCustody returns a one line description of a custody period, e.g.
+// "2020-08-12 to 2022-07-22 Minister of A > Minister of B (Gotabaya Rajapaksa)"
+func DescribeCustody(custody models.Custody) string {
+	line := fmt.Sprintf("%s %s", periodRange(custody.StartTime, custody.EndTime), strings.Join(custody.Ministers, " > "))
+	if len(custody.Presidents) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(custody.Presidents, ", "))
+	}
+	return line
+}
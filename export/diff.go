@@ -0,0 +1,107 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/models"
+)
+
+// WriteDiffJSON writes the changes between two dates as indented JSON
+func WriteDiffJSON(w io.Writer, diff *models.OrgChartDiff) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		return fmt.Errorf("failed to write diff JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteDiffText writes the changes between two dates as a changelog grouped by kind of entity
+func WriteDiffText(w io.Writer, diff *models.OrgChartDiff) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes from %s to %s\n", DateOnly(diff.From), DateOnly(diff.To))
+	if len(diff.Changes) == 0 {
+		b.WriteString("\nNo changes\n")
+	}
+
+	section := ""
+	for _, change := range diff.Changes {
+		if title := changeSection(change); title != section {
+			section = title
+			fmt.Fprintf(&b, "\n%s\n", section)
+		}
+		fmt.Fprintf(&b, "  %s\n", DescribeChange(change))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// changeSection returns the changelog section a change is listed under
+func changeSection(change models.OrgChartChange) string {
+	switch {
+	case change.Type == "citizen" && change.Role == "president":
+		return "Presidents"
+	case change.Type == "minister":
+		return "Ministers"
+	case change.Type == "citizen":
+		return "People"
+	default:
+		return "Institutions"
+	}
+}
+
+// DescribeChange returns a one line description of a change, e.g.
+// "~ Minister of A renamed to Minister of B (2022-07-22)"
+func DescribeChange(change models.OrgChartChange) string {
+	var line string
+	switch change.Action {
+	case api.ChangeAdded:
+		line = fmt.Sprintf("+ %s %s added", change.Type, change.Name)
+		if change.NewParent != "" {
+			line += " under " + change.NewParent
+		}
+	case api.ChangeRemoved:
+		if change.Type == "citizen" {
+			line = fmt.Sprintf("- %s removed as %s of %s", change.Name, roleName(change.Role), change.OldParent)
+		} else {
+			line = fmt.Sprintf("- %s %s removed", change.Type, change.Name)
+			if change.OldParent != "" {
+				line += " from " + change.OldParent
+			}
+		}
+	case api.ChangeRenamed:
+		line = fmt.Sprintf("~ %s renamed to %s", strings.Join(change.From, ", "), change.Name)
+	case api.ChangeMerged:
+		line = fmt.Sprintf("~ %s merged into %s", strings.Join(change.From, ", "), change.Name)
+	case api.ChangeSplit:
+		line = fmt.Sprintf("~ %s split into %s", change.Name, strings.Join(change.To, ", "))
+	case api.ChangeMoved:
+		if change.Type == "citizen" {
+			line = fmt.Sprintf("> %s moved as %s from %s to %s", change.Name, roleName(change.Role), change.OldParent, change.NewParent)
+		} else {
+			line = fmt.Sprintf("> %s moved from %s to %s", change.Name, change.OldParent, change.NewParent)
+		}
+	case api.ChangeAppointed:
+		line = fmt.Sprintf("+ %s appointed %s of %s", change.Name, roleName(change.Role), change.NewParent)
+	default:
+		line = fmt.Sprintf("%s %s %s", change.Action, change.Type, change.Name)
+	}
+
+	if change.Date != "" {
+		line += fmt.Sprintf(" (%s)", DateOnly(change.Date))
+	}
+	return line
+}
+
+// roleName makes a role readable, e.g. "cabinet_minister" becomes "cabinet minister"
+func roleName(role string) string {
+	if role == "" {
+		return "appointee"
+	}
+	return strings.ReplaceAll(role, "_", " ")
+}
@@ -0,0 +1,258 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Graph files
+// An exported graph is written as JSON (read back by the import command), as a Cypher script that recreates
+// the graph in an empty Neo4j database, or as the node and relationship CSV files taken by
+// "neo4j-admin database import". Every node has the Entity label plus the label of its major kind.
+
+// graphEntitiesCSVHeader and graphRelationshipsCSVHeader are the neo4j-admin import headers. Metadata and
+// attributes are JSON encoded.
+var (
+	graphEntitiesCSVHeader      = []string{"id:ID", ":LABEL", "kind_minor", "name", "created", "terminated", "metadata", "attributes"}
+	graphRelationshipsCSVHeader = []string{"id", ":START_ID", ":END_ID", ":TYPE", "startTime", "endTime"}
+)
+
+// WriteGraphJSON writes a graph as indented JSON
+func WriteGraphJSON(w io.Writer, graph *models.Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(graph); err != nil {
+		return fmt.Errorf("failed to write graph JSON: %w", err)
+	}
+	return nil
+}
+
+// ReadGraphJSON reads a graph written by WriteGraphJSON
+func ReadGraphJSON(r io.Reader) (*models.Graph, error) {
+	var graph models.Graph
+	if err := json.NewDecoder(r).Decode(&graph); err != nil {
+		return nil, fmt.Errorf("failed to read graph JSON: %w", err)
+	}
+	return &graph, nil
+}
+
+// WriteGraphCypher writes a graph as a Cypher script of CREATE statements
+func WriteGraphCypher(w io.Writer, graph *models.Graph) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Org chart graph exported at %s\n", graph.ExportedAt)
+	b.WriteString("CREATE INDEX entity_id IF NOT EXISTS FOR (n:Entity) ON (n.id);\n")
+
+	for _, entity := range graph.Entities {
+		properties, err := graphEntityProperties(entity)
+		if err != nil {
+			return err
+		}
+		var fields []string
+		for _, property := range properties {
+			fields = append(fields, fmt.Sprintf("%s: %s", property[0], cypherString(property[1])))
+		}
+		fmt.Fprintf(&b, "CREATE (:Entity:%s {%s});\n", cypherIdentifier(entity.Kind.Major), strings.Join(fields, ", "))
+	}
+
+	for _, rel := range graph.Relationships {
+		fields := []string{fmt.Sprintf("id: %s", cypherString(rel.ID)), fmt.Sprintf("startTime: %s", cypherString(rel.StartTime))}
+		if rel.EndTime != "" {
+			fields = append(fields, fmt.Sprintf("endTime: %s", cypherString(rel.EndTime)))
+		}
+		fmt.Fprintf(&b, "MATCH (a:Entity {id: %s}), (b:Entity {id: %s}) CREATE (a)-[:%s {%s}]->(b);\n",
+			cypherString(rel.From), cypherString(rel.To), cypherIdentifier(rel.Name), strings.Join(fields, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphEntityProperties returns the node properties of an entity in a stable order, leaving out empty ones
+func graphEntityProperties(entity models.GraphEntity) ([][2]string, error) {
+	metadata, attributes, err := graphEntityJSON(entity)
+	if err != nil {
+		return nil, err
+	}
+	properties := [][2]string{
+		{"id", entity.ID},
+		{"kind_minor", entity.Kind.Minor},
+		{"name", entity.Name},
+		{"created", entity.Created},
+		{"terminated", entity.Terminated},
+		{"metadata", metadata},
+		{"attributes", attributes},
+	}
+
+	var present [][2]string
+	for _, property := range properties {
+		if property[1] != "" {
+			present = append(present, property)
+		}
+	}
+	return present, nil
+}
+
+// graphEntityJSON encodes the metadata and attributes of an entity, as empty strings when it has none
+func graphEntityJSON(entity models.GraphEntity) (string, string, error) {
+	var metadata, attributes string
+	if len(entity.Metadata) > 0 {
+		data, err := json.Marshal(entity.Metadata)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode metadata of %s: %w", entity.ID, err)
+		}
+		metadata = string(data)
+	}
+	if len(entity.Attributes) > 0 {
+		data, err := json.Marshal(entity.Attributes)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode attributes of %s: %w", entity.ID, err)
+		}
+		attributes = string(data)
+	}
+	return metadata, attributes, nil
+}
+
+// cypherString quotes a Cypher string literal
+func cypherString(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+// cypherIdentifier quotes a label or relationship type
+func cypherIdentifier(value string) string {
+	return "`" + strings.ReplaceAll(value, "`", "``") + "`"
+}
+
+// WriteGraphCSV writes a graph as the node and relationship files of a neo4j-admin import
+func WriteGraphCSV(entitiesW, relationshipsW io.Writer, graph *models.Graph) error {
+	entities := csv.NewWriter(entitiesW)
+	if err := entities.Write(graphEntitiesCSVHeader); err != nil {
+		return fmt.Errorf("failed to write entities CSV: %w", err)
+	}
+	for _, entity := range graph.Entities {
+		metadata, attributes, err := graphEntityJSON(entity)
+		if err != nil {
+			return err
+		}
+		record := []string{entity.ID, "Entity;" + entity.Kind.Major, entity.Kind.Minor, entity.Name, entity.Created, entity.Terminated, metadata, attributes}
+		if err := entities.Write(record); err != nil {
+			return fmt.Errorf("failed to write entities CSV: %w", err)
+		}
+	}
+	entities.Flush()
+	if err := entities.Error(); err != nil {
+		return fmt.Errorf("failed to write entities CSV: %w", err)
+	}
+
+	relationships := csv.NewWriter(relationshipsW)
+	if err := relationships.Write(graphRelationshipsCSVHeader); err != nil {
+		return fmt.Errorf("failed to write relationships CSV: %w", err)
+	}
+	for _, rel := range graph.Relationships {
+		if err := relationships.Write([]string{rel.ID, rel.From, rel.To, rel.Name, rel.StartTime, rel.EndTime}); err != nil {
+			return fmt.Errorf("failed to write relationships CSV: %w", err)
+		}
+	}
+	relationships.Flush()
+	if err := relationships.Error(); err != nil {
+		return fmt.Errorf("failed to write relationships CSV: %w", err)
+	}
+	return nil
+}
+
+// ReadGraphCSV reads the node and relationship files written by WriteGraphCSV
+func ReadGraphCSV(entitiesR, relationshipsR io.Reader) (*models.Graph, error) {
+	graph := &models.Graph{}
+
+	entityRecords, err := readCSVRecords(entitiesR, graphEntitiesCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entities CSV: %w", err)
+	}
+	for _, record := range entityRecords {
+		major := ""
+		for _, label := range strings.Split(record[":LABEL"], ";") {
+			if label != "Entity" && label != "" {
+				major = label
+			}
+		}
+		entity := models.GraphEntity{
+			ID:         record["id:ID"],
+			Kind:       models.Kind{Major: major, Minor: record["kind_minor"]},
+			Name:       record["name"],
+			Created:    record["created"],
+			Terminated: record["terminated"],
+		}
+		if record["metadata"] != "" {
+			if err := json.Unmarshal([]byte(record["metadata"]), &entity.Metadata); err != nil {
+				return nil, fmt.Errorf("invalid metadata of %s: %w", entity.ID, err)
+			}
+		}
+		if record["attributes"] != "" {
+			if err := json.Unmarshal([]byte(record["attributes"]), &entity.Attributes); err != nil {
+				return nil, fmt.Errorf("invalid attributes of %s: %w", entity.ID, err)
+			}
+		}
+		graph.Entities = append(graph.Entities, entity)
+	}
+
+	relationshipRecords, err := readCSVRecords(relationshipsR, graphRelationshipsCSVHeader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relationships CSV: %w", err)
+	}
+	for _, record := range relationshipRecords {
+		graph.Relationships = append(graph.Relationships, models.GraphRelationship{
+			ID:        record["id"],
+			Name:      record[":TYPE"],
+			From:      record[":START_ID"],
+			To:        record[":END_ID"],
+			StartTime: record["startTime"],
+			EndTime:   record["endTime"],
+		})
+	}
+
+	return graph, nil
+}
+
+// readCSVRecords reads CSV rows as maps of column name to value, requiring the given columns
+func readCSVRecords(r io.Reader, required []string) ([]map[string]string, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range rows[0] {
+		columns[strings.TrimSpace(header)] = i
+	}
+	var missing []string
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	var records []map[string]string
+	for _, row := range rows[1:] {
+		record := make(map[string]string)
+		for column, i := range columns {
+			if i < len(row) {
+				record[column] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/models"
+)
+
+// WriteLineageJSON writes lineages as indented JSON
+func WriteLineageJSON(w io.Writer, lineages []models.Lineage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lineages); err != nil {
+		return fmt.Errorf("failed to write lineage JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteLineageText writes each lineage as its ancestry, its descendants and the periods every entity of the
+// lineage was held
+func WriteLineageText(w io.Writer, lineages []models.Lineage) error {
+	var b strings.Builder
+	for i, lineage := range lineages {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s (%s %s)\n", lineage.Name, lineage.Type, lineage.ID)
+
+		b.WriteString("\nAncestry\n")
+		if len(lineage.Ancestors) == 0 {
+			b.WriteString("  none\n")
+		}
+		for _, event := range lineage.Ancestors {
+			fmt.Fprintf(&b, "  %s\n", DescribeLineageEvent(event))
+		}
+
+		b.WriteString("\nDescendants\n")
+		if len(lineage.Descendants) == 0 {
+			b.WriteString("  none\n")
+		}
+		for _, event := range lineage.Descendants {
+			fmt.Fprintf(&b, "  %s\n", DescribeLineageEvent(event))
+		}
+
+		b.WriteString("\nHeld\n")
+		for _, entity := range lineage.Entities {
+			fmt.Fprintf(&b, "  %s (%s)\n", entity.Name, entity.ID)
+			for _, period := range entity.Periods {
+				line := fmt.Sprintf("    %s under %s", periodRange(period.StartTime, period.EndTime), period.Parent)
+				if period.President != "" && period.President != period.Parent {
+					line += fmt.Sprintf(" (president %s)", period.President)
+				}
+				fmt.Fprintf(&b, "%s\n", line)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DescribeLineageEvent returns a one line description of a lineage event, e.g.
+// "2022-07-22 Minister of A renamed to Minister of B (Ranil Wickremesinghe; gazette 2289-43)"
+func DescribeLineageEvent(event models.LineageEvent) string {
+	var line string
+	switch event.Action {
+	case api.ChangeMerged:
+		line = fmt.Sprintf("%s %s merged into %s", DateOnly(event.Date), event.FromName, event.ToName)
+	case api.ChangeSplit:
+		line = fmt.Sprintf("%s %s split into %s", DateOnly(event.Date), event.FromName, event.ToName)
+	default:
+		line = fmt.Sprintf("%s %s renamed to %s", DateOnly(event.Date), event.FromName, event.ToName)
+	}
+
+	var details []string
+	if event.President != "" {
+		details = append(details, event.President)
+	}
+	if len(event.Gazettes) > 0 {
+		details = append(details, "gazette "+strings.Join(event.Gazettes, ", "))
+	}
+	if len(details) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(details, "; "))
+	}
+	return line
+}
+
+// periodRange formats a period as "2022-07-22 to 2024-09-23", or "since 2022-07-22" while it lasts
+func periodRange(startISO, endISO string) string {
+	if endISO == "" {
+		return "since " + DateOnly(startISO)
+	}
+	return DateOnly(startISO) + " to " + DateOnly(endISO)
+}
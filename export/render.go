@@ -0,0 +1,277 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Chart rendering
+// A snapshot is drawn as a tree from the government (or the selected president or minister) down to the
+// institutions and nested units of each minister. Every minister is drawn as a cluster holding its
+// institutions and appointees. For snapshots of a period, relationships that ended within the period are
+// drawn dashed and grey.
+
+// RenderOptions selects what is drawn besides ministers and institutions
+type RenderOptions struct {
+	Appointees bool // people appointed to ministers and institutions
+	Sources    bool // the gazettes each relationship was taken from
+}
+
+// SelectSubtree returns the first node of the given type and name in the snapshot, e.g. a president
+// ("citizen") or a minister. Returns an error when the name is not found or names several entities.
+func SelectSubtree(snapshot *models.Snapshot, nodeType, name string) (models.SnapshotNode, error) {
+	var matches []models.SnapshotNode
+	var walk func(node models.SnapshotNode)
+	walk = func(node models.SnapshotNode) {
+		if node.Type == nodeType && node.Name == name {
+			for _, match := range matches {
+				if match.ID == node.ID {
+					return
+				}
+			}
+			matches = append(matches, node)
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(snapshot.Government)
+
+	if len(matches) == 0 {
+		return models.SnapshotNode{}, fmt.Errorf("no %s named '%s' in the snapshot", nodeType, name)
+	}
+	if len(matches) > 1 {
+		return models.SnapshotNode{}, fmt.Errorf("%d entities of type %s are named '%s' in the snapshot", len(matches), nodeType, name)
+	}
+	return matches[0], nil
+}
+
+// chartWriter collects the statements of a chart, declaring each entity once
+type chartWriter struct {
+	b        strings.Builder
+	until    string
+	options  RenderOptions
+	declared map[string]bool
+}
+
+// terminated reports whether the relationship to a node ended within the drawn period
+func (w *chartWriter) terminated(node models.SnapshotNode) bool {
+	return w.until != "" && node.EndTime != "" && node.EndTime <= w.until
+}
+
+// visibleChildren drops the appointees of ministers and institutions unless they are drawn
+func (w *chartWriter) visibleChildren(node models.SnapshotNode) []models.SnapshotNode {
+	var children []models.SnapshotNode
+	for _, child := range node.Children {
+		if child.Type == "citizen" && child.Relationship != "AS_PRESIDENT" && !w.options.Appointees {
+			continue
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+// WriteDOT renders the tree below root as a GraphViz DOT digraph
+func WriteDOT(w io.Writer, snapshot *models.Snapshot, root models.SnapshotNode, options RenderOptions) error {
+	chart := &chartWriter{until: snapshot.Until, options: options, declared: make(map[string]bool)}
+	chart.b.WriteString("digraph orgchart {\n")
+	chart.b.WriteString("  rankdir=LR;\n")
+	chart.b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	chart.b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	chart.dotNode(root, "  ")
+	chart.dotChildren(root, "  ")
+	chart.b.WriteString("}\n")
+
+	_, err := io.WriteString(w, chart.b.String())
+	return err
+}
+
+// dotNode declares an entity the first time it is drawn
+func (w *chartWriter) dotNode(node models.SnapshotNode, indent string) {
+	if w.declared[node.ID] {
+		return
+	}
+	w.declared[node.ID] = true
+
+	attributes := []string{fmt.Sprintf("label=%s", dotQuote(node.Name))}
+	switch node.Type {
+	case "government":
+		attributes = append(attributes, "shape=doubleoctagon")
+	case "citizen":
+		attributes = append(attributes, "shape=ellipse")
+	case "minister":
+		attributes = append(attributes, "style=\"rounded,bold\"")
+	}
+	if w.terminated(node) {
+		attributes = append(attributes, "color=gray", "fontcolor=gray")
+	}
+	fmt.Fprintf(&w.b, "%s%s [%s];\n", indent, dotQuote(node.ID), strings.Join(attributes, ", "))
+}
+
+// dotChildren draws the children of a node, clustering each minister with what it holds
+func (w *chartWriter) dotChildren(node models.SnapshotNode, indent string) {
+	for _, child := range w.visibleChildren(node) {
+		if child.Type == "minister" {
+			fmt.Fprintf(&w.b, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+child.ID))
+			fmt.Fprintf(&w.b, "%s  label=%s;\n", indent, dotQuote(child.Name))
+			fmt.Fprintf(&w.b, "%s  style=dashed;\n", indent)
+			w.dotNode(child, indent+"  ")
+			w.dotClusterNodes(child, indent+"  ")
+			fmt.Fprintf(&w.b, "%s}\n", indent)
+		} else {
+			w.dotNode(child, indent)
+		}
+		w.dotEdge(node, child, indent)
+		w.dotChildren(child, indent)
+	}
+}
+
+// dotClusterNodes declares everything below a minister inside its cluster
+func (w *chartWriter) dotClusterNodes(node models.SnapshotNode, indent string) {
+	for _, child := range w.visibleChildren(node) {
+		w.dotNode(child, indent)
+		w.dotClusterNodes(child, indent)
+	}
+}
+
+// dotEdge draws the relationship from a parent to a child and, when requested, to its source gazettes
+func (w *chartWriter) dotEdge(parent, child models.SnapshotNode, indent string) {
+	attributes := []string{fmt.Sprintf("label=%s", dotQuote(edgeLabel(child)))}
+	if w.terminated(child) {
+		attributes = append(attributes, "style=dashed", "color=gray", "fontcolor=gray")
+	}
+	fmt.Fprintf(&w.b, "%s%s -> %s [%s];\n", indent, dotQuote(parent.ID), dotQuote(child.ID), strings.Join(attributes, ", "))
+
+	if !w.options.Sources {
+		return
+	}
+	for _, gazette := range child.Sources {
+		gazetteID := "gazette_" + gazette
+		if !w.declared[gazetteID] {
+			w.declared[gazetteID] = true
+			fmt.Fprintf(&w.b, "%s%s [label=%s, shape=note, fontsize=9];\n", indent, dotQuote(gazetteID), dotQuote("Gazette "+gazette))
+		}
+		fmt.Fprintf(&w.b, "%s%s -> %s [style=dotted, arrowhead=none];\n", indent, dotQuote(child.ID), dotQuote(gazetteID))
+	}
+}
+
+// WriteMermaid renders the tree below root as a Mermaid flowchart
+func WriteMermaid(w io.Writer, snapshot *models.Snapshot, root models.SnapshotNode, options RenderOptions) error {
+	chart := &chartWriter{until: snapshot.Until, options: options, declared: make(map[string]bool)}
+	chart.b.WriteString("flowchart LR\n")
+	chart.mermaidNode(root, "  ")
+	var terminated []string
+	chart.mermaidChildren(root, "  ", &terminated)
+	chart.b.WriteString("  classDef terminated stroke-dasharray: 5 5,color:#888,stroke:#888\n")
+	if len(terminated) > 0 {
+		fmt.Fprintf(&chart.b, "  class %s terminated\n", strings.Join(terminated, ","))
+	}
+
+	_, err := io.WriteString(w, chart.b.String())
+	return err
+}
+
+// mermaidNode declares an entity the first time it is drawn
+func (w *chartWriter) mermaidNode(node models.SnapshotNode, indent string) {
+	if w.declared[node.ID] {
+		return
+	}
+	w.declared[node.ID] = true
+
+	id, label := mermaidID(node.ID), mermaidLabel(node.Name)
+	switch node.Type {
+	case "government":
+		fmt.Fprintf(&w.b, "%s%s{{%s}}\n", indent, id, label)
+	case "citizen":
+		fmt.Fprintf(&w.b, "%s%s([%s])\n", indent, id, label)
+	default:
+		fmt.Fprintf(&w.b, "%s%s[%s]\n", indent, id, label)
+	}
+}
+
+// mermaidChildren draws the children of a node, clustering each minister with what it holds. The IDs of
+// entities whose relationship ended within the period are collected in terminated.
+func (w *chartWriter) mermaidChildren(node models.SnapshotNode, indent string, terminated *[]string) {
+	for _, child := range w.visibleChildren(node) {
+		if child.Type == "minister" {
+			fmt.Fprintf(&w.b, "%ssubgraph %s[%s]\n", indent, mermaidID("cluster_"+child.ID), mermaidLabel(child.Name))
+			w.mermaidNode(child, indent+"  ")
+			w.mermaidClusterNodes(child, indent+"  ")
+			fmt.Fprintf(&w.b, "%send\n", indent)
+		} else {
+			w.mermaidNode(child, indent)
+		}
+
+		arrow := "-->"
+		if w.terminated(child) {
+			arrow = "-.->"
+			*terminated = append(*terminated, mermaidID(child.ID))
+		}
+		fmt.Fprintf(&w.b, "%s%s %s|%s| %s\n", indent, mermaidID(node.ID), arrow, mermaidLabel(edgeLabel(child)), mermaidID(child.ID))
+
+		if w.options.Sources {
+			for _, gazette := range child.Sources {
+				gazetteID := "gazette_" + gazette
+				if !w.declared[gazetteID] {
+					w.declared[gazetteID] = true
+					fmt.Fprintf(&w.b, "%s%s>%s]\n", indent, mermaidID(gazetteID), mermaidLabel("Gazette "+gazette))
+				}
+				fmt.Fprintf(&w.b, "%s%s -.- %s\n", indent, mermaidID(child.ID), mermaidID(gazetteID))
+			}
+		}
+
+		w.mermaidChildren(child, indent, terminated)
+	}
+}
+
+// mermaidClusterNodes declares everything below a minister inside its cluster
+func (w *chartWriter) mermaidClusterNodes(node models.SnapshotNode, indent string) {
+	for _, child := range w.visibleChildren(node) {
+		w.mermaidNode(child, indent)
+		w.mermaidClusterNodes(child, indent)
+	}
+}
+
+// edgeLabel describes the relationship to a node: its role or relationship and its period
+func edgeLabel(node models.SnapshotNode) string {
+	label := node.Relationship
+	if node.Role != "" {
+		label = roleName(node.Role)
+	}
+	if node.StartTime != "" {
+		label += " " + DateOnly(node.StartTime)
+		if node.EndTime != "" {
+			label += " to " + DateOnly(node.EndTime)
+		}
+	}
+	return label
+}
+
+// dotQuote quotes a DOT identifier or label
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// mermaidID turns an entity ID into a Mermaid node ID (letters, digits and underscores)
+func mermaidID(value string) string {
+	var b strings.Builder
+	b.WriteString("n_")
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// mermaidLabel quotes a Mermaid label
+func mermaidLabel(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,84 @@
+// Package export writes org chart views read through the API client to files: JSON and CSV snapshots and
+// the other renderings built on them.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// snapshotCSVHeader lists the columns of a snapshot CSV. Each row is one node with its parent, so the tree
+// can be rebuilt from the parent_id column.
+var snapshotCSVHeader = []string{
+	"date", "level", "parent_id", "parent_name", "id", "name", "type", "relationship", "role", "start_date", "sources",
+}
+
+// WriteSnapshotJSON writes a snapshot as an indented, nested JSON tree
+func WriteSnapshotJSON(w io.Writer, snapshot *models.Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to write snapshot JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteSnapshotCSV writes a snapshot as one CSV row per node, parents before their children
+func WriteSnapshotCSV(w io.Writer, snapshot *models.Snapshot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(snapshotCSVHeader); err != nil {
+		return fmt.Errorf("failed to write snapshot CSV: %w", err)
+	}
+
+	date := DateOnly(snapshot.Date)
+	var writeNode func(node models.SnapshotNode, parent *models.SnapshotNode, level int) error
+	writeNode = func(node models.SnapshotNode, parent *models.SnapshotNode, level int) error {
+		parentID, parentName := "", ""
+		if parent != nil {
+			parentID, parentName = parent.ID, parent.Name
+		}
+		err := writer.Write([]string{
+			date,
+			strconv.Itoa(level),
+			parentID,
+			parentName,
+			node.ID,
+			node.Name,
+			node.Type,
+			node.Relationship,
+			node.Role,
+			DateOnly(node.StartTime),
+			strings.Join(node.Sources, ";"),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write snapshot CSV: %w", err)
+		}
+		for _, child := range node.Children {
+			if err := writeNode(child, &node, level+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := writeNode(snapshot.Government, nil, 0); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// DateOnly shortens an RFC3339 time to its YYYY-MM-DD date
+func DateOnly(value string) string {
+	if len(value) >= len("2006-01-02") {
+		return value[:len("2006-01-02")]
+	}
+	return value
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"orgchart_nexoan/models"
+)
+
+// statsCSVHeader lists the columns of a statistics CSV, one row per president and year
+var statsCSVHeader = []string{
+	"president", "year", "from", "to", "active_ministers", "active_departments", "ministers_created",
+	"ministers_renamed", "ministers_merged", "ministers_split", "ministers_terminated", "portfolios_ended",
+	"average_portfolio_days", "people_appointed", "people_removed", "people_moved", "departments_moved",
+}
+
+// WriteStatsJSON writes statistics as indented JSON
+func WriteStatsJSON(w io.Writer, stats []models.PresidencyStats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(stats); err != nil {
+		return fmt.Errorf("failed to write stats JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteStatsCSV writes statistics as one CSV row per president and year
+func WriteStatsCSV(w io.Writer, stats []models.PresidencyStats) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(statsCSVHeader); err != nil {
+		return fmt.Errorf("failed to write stats CSV: %w", err)
+	}
+
+	for _, row := range stats {
+		record := []string{
+			row.President,
+			strconv.Itoa(row.Year),
+			DateOnly(row.From),
+			DateOnly(row.To),
+			strconv.Itoa(row.ActiveMinisters),
+			strconv.Itoa(row.ActiveDepartments),
+			strconv.Itoa(row.MinistersCreated),
+			strconv.Itoa(row.MinistersRenamed),
+			strconv.Itoa(row.MinistersMerged),
+			strconv.Itoa(row.MinistersSplit),
+			strconv.Itoa(row.MinistersTerminated),
+			strconv.Itoa(row.PortfoliosEnded),
+			strconv.FormatFloat(row.AveragePortfolioDays, 'f', 1, 64),
+			strconv.Itoa(row.PeopleAppointed),
+			strconv.Itoa(row.PeopleRemoved),
+			strconv.Itoa(row.PeopleMoved),
+			strconv.Itoa(row.DepartmentsMoved),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write stats CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write stats CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/models"
+)
+
+// WritePersonTimelineJSON writes a person timeline as indented JSON
+func WritePersonTimelineJSON(w io.Writer, timeline *models.PersonTimeline) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(timeline); err != nil {
+		return fmt.Errorf("failed to write timeline JSON: %w", err)
+	}
+	return nil
+}
+
+// WritePersonTimelineText writes the presidential terms and the appointments of a person, oldest first
+func WritePersonTimelineText(w io.Writer, timeline *models.PersonTimeline) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", timeline.Name, timeline.ID)
+
+	if len(timeline.Terms) > 0 {
+		b.WriteString("\nPresidential terms\n")
+		for _, term := range timeline.Terms {
+			fmt.Fprintf(&b, "  %s\n", periodRange(term.StartTime, term.EndTime))
+		}
+	}
+
+	b.WriteString("\nAppointments\n")
+	if len(timeline.Appointments) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, appointment := range timeline.Appointments {
+		fmt.Fprintf(&b, "  %s\n", DescribeAppointment(appointment))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DescribeAppointment returns a one line description of an appointment and how it ended, e.g.
+// "2022-07-22 to 2023-01-10 cabinet minister of Minister of A (Ranil Wickremesinghe), renamed to Minister of B"
+func DescribeAppointment(appointment models.CareerAppointment) string {
+	line := fmt.Sprintf("%s %s of %s", periodRange(appointment.StartTime, appointment.EndTime), roleName(appointment.Role), appointment.ParentName)
+	if appointment.President != "" {
+		line += fmt.Sprintf(" (%s)", appointment.President)
+	}
+
+	switch appointment.EndReason {
+	case "":
+	case api.AppointmentRenamed:
+		line += ", carried over when renamed to " + appointment.Successor
+	case api.AppointmentMerged:
+		line += ", ended when merged into " + appointment.Successor
+	case api.AppointmentSplit:
+		line += ", ended when split into " + appointment.Successor
+	case api.AppointmentMoved:
+		line += ", moved to " + appointment.Successor
+	case api.AppointmentParentTerminated:
+		line += fmt.Sprintf(", ended with the %s", appointment.ParentType)
+	default:
+		line += ", " + appointment.EndReason
+	}
+	return line
+}
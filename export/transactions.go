@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"orgchart_nexoan/models"
+)
+
+// Transaction files
+// Transactions rebuilt from a graph are written as the CSVs read by the tool, one file per transaction type
+// and directory, together with a shell script loading the directories in order into an empty backend.
+
+// WriteTransactionCSV writes a transaction file with its header
+func WriteTransactionCSV(w io.Writer, file models.TransactionFile) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(file.Columns); err != nil {
+		return fmt.Errorf("failed to write %s transactions: %w", file.Type, err)
+	}
+	if err := writer.WriteAll(file.Rows); err != nil {
+		return fmt.Errorf("failed to write %s transactions: %w", file.Type, err)
+	}
+	return nil
+}
+
+// WriteLoadScript writes a bash script loading the directories in order with the orgchart binary of the
+// current directory. The first run initialises the government.
+func WriteLoadScript(w io.Writer, directories []models.TransactionDirectory) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/bash\n\n")
+	b.WriteString("# Rebuilds the exported org chart in an empty backend. Run from the directory of the orgchart binary.\n")
+	b.WriteString("DATA=\"$(cd \"$(dirname \"$0\")\" && pwd)\"\n")
+
+	date := ""
+	for i, directory := range directories {
+		if directory.Date != date {
+			date = directory.Date
+			fmt.Fprintf(&b, "\n# %s\n", DateOnly(date))
+		}
+		fmt.Fprintf(&b, "./orgchart -data \"$DATA/%s/\"", shellEscape(directory.Path))
+		if directory.ProcessType != "organisation" {
+			fmt.Fprintf(&b, " -type %s", directory.ProcessType)
+		}
+		if i == 0 {
+			b.WriteString(" -init")
+		}
+		b.WriteString(" || exit 1\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellEscape escapes the characters that are special inside a double quoted bash string
+func shellEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(value)
+}
@@ -164,3 +164,279 @@ func UnmarshalName(data []byte) (string, error) {
 // 	s.Name = string(decoded)
 // 	return nil
 // }
+
+// TransitionSummary describes what a presidency transition changed
+type TransitionSummary struct {
+	OutgoingPresident    string   `json:"outgoingPresident"`
+	IncomingPresident    string   `json:"incomingPresident"`
+	Date                 string   `json:"date"`
+	MinistersTransferred []string `json:"ministersTransferred"`
+	MinistersTerminated  []string `json:"ministersTerminated"`
+	DepartmentsCarried   int      `json:"departmentsCarried"`
+	AppointeesPolicy     string   `json:"appointeesPolicy"`
+}
+
+// AuditEntry records a correction made to an existing relationship
+type AuditEntry struct {
+	Action         string `json:"action"`
+	TransactionID  string `json:"transactionId"`
+	RelationshipID string `json:"relationshipId"`
+	ReplacedByID   string `json:"replacedById,omitempty"`
+	Date           string `json:"date"`
+	Reason         string `json:"reason"`
+	OldStartTime   string `json:"oldStartTime"`
+	OldEndTime     string `json:"oldEndTime"`
+	NewStartTime   string `json:"newStartTime"`
+	NewEndTime     string `json:"newEndTime"`
+}
+
+// PersonIdentity is an entry of the person alias registry, linking a canonical person ID to the
+// names the person is known by
+type PersonIdentity struct {
+	PersonID string   `json:"person_id"`
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases"`
+}
+
+// MinisterClassification describes a minister with its classification attributes as of a date
+type MinisterClassification struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Category        string `json:"category"`
+	GazettePosition int    `json:"gazettePosition,omitempty"`
+}
+
+// DocumentMetadata holds the publication details of a gazette Document entity
+type DocumentMetadata struct {
+	ID            string `json:"id"`
+	GazetteNumber string `json:"gazetteNumber"`
+	Date          string `json:"date,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Part          string `json:"part,omitempty"`
+	Language      string `json:"language,omitempty"`
+	Pages         int    `json:"pages,omitempty"`
+}
+
+// DocumentInForce is an entry in the chain of instruments in force for a gazette. Relationship and Target
+// tell how the document changes an earlier document; both are empty for the gazette itself.
+type DocumentInForce struct {
+	ID            string `json:"id"`
+	GazetteNumber string `json:"gazetteNumber"`
+	Relationship  string `json:"relationship,omitempty"`
+	Target        string `json:"target,omitempty"`
+	EffectiveDate string `json:"effectiveDate,omitempty"`
+}
+
+// SnapshotNode is an entity of an org chart snapshot, with the relationship linking it to its parent node
+// and the gazettes that relationship was sourced from
+type SnapshotNode struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Type         string         `json:"type"`
+	Relationship string         `json:"relationship,omitempty"`
+	Role         string         `json:"role,omitempty"`
+	StartTime    string         `json:"startTime,omitempty"`
+	EndTime      string         `json:"endTime,omitempty"`
+	Sources      []string       `json:"sources,omitempty"`
+	Children     []SnapshotNode `json:"children,omitempty"`
+}
+
+// Snapshot is the org chart as it stood on a date, rooted at the government. Snapshots of a period start at
+// Date and end at Until.
+type Snapshot struct {
+	Date       string       `json:"date"`
+	Until      string       `json:"until,omitempty"`
+	Government SnapshotNode `json:"government"`
+}
+
+// MinisterView is a minister as it stood on a date, with its institutions and appointees
+type MinisterView struct {
+	Date      string       `json:"date"`
+	President string       `json:"president"`
+	Minister  SnapshotNode `json:"minister"`
+}
+
+// OrgChartChange is a change of the org chart between two dates. From lists the previous names of renamed
+// and merged entities and To the entities a split entity was divided into.
+type OrgChartChange struct {
+	Action    string   `json:"action"`
+	Type      string   `json:"type"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Role      string   `json:"role,omitempty"`
+	From      []string `json:"from,omitempty"`
+	To        []string `json:"to,omitempty"`
+	OldParent string   `json:"oldParent,omitempty"`
+	NewParent string   `json:"newParent,omitempty"`
+	President string   `json:"president,omitempty"`
+	Date      string   `json:"date,omitempty"`
+}
+
+// OrgChartDiff lists the changes of the org chart between two dates
+type OrgChartDiff struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Changes []OrgChartChange `json:"changes"`
+}
+
+// GraphEntity is an entity of an exported graph with its metadata and the time-based values of its known
+// attributes
+type GraphEntity struct {
+	ID         string                      `json:"id"`
+	Kind       Kind                        `json:"kind"`
+	Name       string                      `json:"name"`
+	Created    string                      `json:"created"`
+	Terminated string                      `json:"terminated,omitempty"`
+	Metadata   map[string]interface{}      `json:"metadata,omitempty"`
+	Attributes map[string][]TimeBasedValue `json:"attributes,omitempty"`
+}
+
+// GraphRelationship is a relationship of an exported graph, from the entity owning it to the related entity
+type GraphRelationship struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// Graph is the entity graph read through the Query API, in a form that can be re-loaded through the
+// Update API
+type Graph struct {
+	ExportedAt    string              `json:"exportedAt"`
+	Entities      []GraphEntity       `json:"entities"`
+	Relationships []GraphRelationship `json:"relationships"`
+}
+
+// LineagePeriod is a period an entity of a lineage was held: by a president for ministers, by a minister or
+// parent institution for institutions
+type LineagePeriod struct {
+	Parent    string `json:"parent"`
+	President string `json:"president,omitempty"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// LineageEntity is an entity of a lineage with the periods it was held. An entity reused by a later rename
+// has several periods.
+type LineageEntity struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Periods []LineagePeriod `json:"periods,omitempty"`
+}
+
+// LineageEvent is a rename, merge or split linking two entities of a lineage, with the president the
+// replaced entity was held under and the gazettes the change was sourced from
+type LineageEvent struct {
+	Action       string   `json:"action"`
+	Relationship string   `json:"relationship"`
+	FromID       string   `json:"fromId"`
+	FromName     string   `json:"fromName"`
+	ToID         string   `json:"toId"`
+	ToName       string   `json:"toName"`
+	Date         string   `json:"date"`
+	President    string   `json:"president,omitempty"`
+	Gazettes     []string `json:"gazettes,omitempty"`
+}
+
+// Lineage is the ancestry and the descendants of an entity across renames, merges and splits, oldest first
+type Lineage struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Ancestors   []LineageEvent  `json:"ancestors"`
+	Descendants []LineageEvent  `json:"descendants"`
+	Entities    []LineageEntity `json:"entities"`
+}
+
+// CareerAppointment is a position a person held on a minister or institution. EndReason tells how an ended
+// appointment ended and Successor names the entity it was carried to or the person moved to.
+type CareerAppointment struct {
+	Role         string   `json:"role"`
+	Relationship string   `json:"relationship"`
+	ParentID     string   `json:"parentId"`
+	ParentName   string   `json:"parentName"`
+	ParentType   string   `json:"parentType"`
+	President    string   `json:"president,omitempty"`
+	StartTime    string   `json:"startTime"`
+	EndTime      string   `json:"endTime,omitempty"`
+	EndReason    string   `json:"endReason,omitempty"`
+	Successor    string   `json:"successor,omitempty"`
+	Gazettes     []string `json:"gazettes,omitempty"`
+}
+
+// PresidentialTerm is a period a person held the presidency
+type PresidentialTerm struct {
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+// PersonTimeline is the career of a person across presidencies, oldest first
+type PersonTimeline struct {
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Terms        []PresidentialTerm  `json:"terms,omitempty"`
+	Appointments []CareerAppointment `json:"appointments"`
+}
+
+// Custody is a period a department was held by one minister, or by a chain of ministers renamed into each
+// other. Ministers lists the names of the chain, oldest first.
+type Custody struct {
+	MinisterIDs []string `json:"ministerIds"`
+	Ministers   []string `json:"ministers"`
+	Presidents  []string `json:"presidents,omitempty"`
+	StartTime   string   `json:"startTime"`
+	EndTime     string   `json:"endTime,omitempty"`
+}
+
+// DepartmentCustody is the custody history of a department. Handovers counts the times it changed hands.
+type DepartmentCustody struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Handovers  int       `json:"handovers"`
+	Custodians []Custody `json:"custodians"`
+}
+
+// PresidencyStats are the statistics of one president in one calendar year, over the period From (inclusive)
+// to To (exclusive). Active counts include every minister and department held at some point of the period;
+// the other counts are changes dated within the period.
+type PresidencyStats struct {
+	President            string  `json:"president"`
+	Year                 int     `json:"year"`
+	From                 string  `json:"from"`
+	To                   string  `json:"to"`
+	ActiveMinisters      int     `json:"activeMinisters"`
+	ActiveDepartments    int     `json:"activeDepartments"`
+	MinistersCreated     int     `json:"ministersCreated"`
+	MinistersRenamed     int     `json:"ministersRenamed"`
+	MinistersMerged      int     `json:"ministersMerged"`
+	MinistersSplit       int     `json:"ministersSplit"`
+	MinistersTerminated  int     `json:"ministersTerminated"`
+	PortfoliosEnded      int     `json:"portfoliosEnded"`
+	AveragePortfolioDays float64 `json:"averagePortfolioDays"`
+	PeopleAppointed      int     `json:"peopleAppointed"`
+	PeopleRemoved        int     `json:"peopleRemoved"`
+	PeopleMoved          int     `json:"peopleMoved"`
+	DepartmentsMoved     int     `json:"departmentsMoved"`
+}
+
+// TransactionFile is a transaction CSV of a load directory, e.g. MOVE.csv. Rows hold the values of Columns.
+type TransactionFile struct {
+	Type    string     `json:"type"`
+	Name    string     `json:"name"` // file name in the directory
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// TransactionDirectory is a directory of transaction CSVs loaded in one run of the tool, in the layout of the
+// data tree, e.g. orgchart/<president>/<date>. ProcessType is the -type the directory is loaded with.
+type TransactionDirectory struct {
+	Path        string            `json:"path"`
+	ProcessType string            `json:"processType"`
+	Date        string            `json:"date"`
+	Files       []TransactionFile `json:"files"`
+}
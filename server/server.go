@@ -0,0 +1,317 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/models"
+)
+
+// Read-only HTTP service
+// The service exposes the views computed by the API client (snapshots, ministers, person timelines,
+// lineages and diffs) as JSON, so applications do not have to walk the raw entities of the Query API.
+// Responses are cached in memory for a configurable time and carry an ETag, so clients can revalidate with
+// If-None-Match and get a 304 Not Modified while the view is unchanged. Nothing is ever written.
+
+// errorResponse is the body of error responses
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// requestError is an error caused by the request rather than by the backend
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// badRequest and notFound create errors answered with 400 and 404
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &requestError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+// cachedResponse is an encoded view with its ETag
+type cachedResponse struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// Server answers the view requests with the given client, caching responses for the given time
+type Server struct {
+	client *api.Client
+	ttl    time.Duration
+	mux    *http.ServeMux
+
+	mu    sync.Mutex
+	cache map[string]cachedResponse
+	swept time.Time // last time expired responses were evicted
+}
+
+// New creates a server reading through the client. Responses are cached for ttl (not at all when zero).
+func New(client *api.Client, ttl time.Duration) *Server {
+	s := &Server{
+		client: client,
+		ttl:    ttl,
+		mux:    http.NewServeMux(),
+		cache:  make(map[string]cachedResponse),
+	}
+	s.mux.HandleFunc("GET /snapshot", s.view(s.snapshot, "date"))
+	s.mux.HandleFunc("GET /minister", s.view(s.minister, "date", "id", "name"))
+	s.mux.HandleFunc("GET /person", s.view(s.person, "id", "name"))
+	s.mux.HandleFunc("GET /lineage", s.view(s.lineage, "id", "name"))
+	s.mux.HandleFunc("GET /diff", s.view(s.diff, "from", "to"))
+	return s
+}
+
+// ServeHTTP routes a request to its view
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// view turns a function computing a view into a handler answering with cached JSON and an ETag.
+// Responses are cached by the given parameters only, so unknown parameters do not split the cache.
+func (s *Server) view(compute func(r *http.Request) (interface{}, error), params ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := cacheKey(r, params)
+
+		response, ok := s.cached(key)
+		if !ok {
+			value, err := compute(r)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			response, err = encode(value)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			s.store(key, response)
+		}
+
+		w.Header().Set("ETag", response.etag)
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(s.ttl.Seconds())))
+		if etagMatches(r.Header.Get("If-None-Match"), response.etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(response.body)
+	}
+}
+
+// cacheKey builds the cache key of a request from its path and the trimmed values of the given parameters
+func cacheKey(r *http.Request, params []string) string {
+	values := url.Values{}
+	for _, param := range params {
+		if value := strings.TrimSpace(r.URL.Query().Get(param)); value != "" {
+			values.Set(param, value)
+		}
+	}
+	return r.URL.Path + "?" + values.Encode()
+}
+
+// cached returns the cached response for a key unless it expired
+func (s *Server) cached(key string) (cachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, ok := s.cache[key]
+	if !ok || time.Now().After(response.expires) {
+		delete(s.cache, key)
+		return cachedResponse{}, false
+	}
+	return response, true
+}
+
+// store caches a response for the configured time
+func (s *Server) store(key string, response cachedResponse) {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	response.expires = now.Add(s.ttl)
+	s.cache[key] = response
+
+	// Evict the expired responses of keys that are not requested again, at most once per ttl
+	if now.Sub(s.swept) < s.ttl {
+		return
+	}
+	for cachedKey, entry := range s.cache {
+		if now.After(entry.expires) {
+			delete(s.cache, cachedKey)
+		}
+	}
+	s.swept = now
+}
+
+// encode writes a view as indented JSON, with the ETag of the encoded body
+func encode(value interface{}) (cachedResponse, error) {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return cachedResponse{}, fmt.Errorf("failed to encode response: %w", err)
+	}
+	sum := sha256.Sum256(body.Bytes())
+	return cachedResponse{body: body.Bytes(), etag: `"` + hex.EncodeToString(sum[:16]) + `"`}, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeError answers with the status of a request error, 404 Not Found when the API found no matching
+// entity, or 502 Bad Gateway when the backend failed
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadGateway
+	var requestErr *requestError
+	switch {
+	case errors.As(err, &requestErr):
+		status = requestErr.status
+	case errors.Is(err, api.ErrNotFound):
+		status = http.StatusNotFound
+	default:
+		log.Printf("%s %s: %v", r.Method, r.URL, err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+// dateParam parses a YYYY-MM-DD query parameter into RFC3339
+func dateParam(r *http.Request, name string) (string, error) {
+	value := strings.TrimSpace(r.URL.Query().Get(name))
+	if value == "" {
+		return "", badRequest("the %s parameter is required (YYYY-MM-DD)", name)
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", badRequest("invalid %s '%s', expected YYYY-MM-DD", name, value)
+	}
+	return date.Format(time.RFC3339), nil
+}
+
+// idOrName returns the id or name query parameter, requiring exactly one of them
+func idOrName(r *http.Request) (string, string, error) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if (id == "") == (name == "") {
+		return "", "", badRequest("either the id or the name parameter is required")
+	}
+	return id, name, nil
+}
+
+// snapshot answers GET /snapshot?date=YYYY-MM-DD
+func (s *Server) snapshot(r *http.Request) (interface{}, error) {
+	dateISO, err := dateParam(r, "date")
+	if err != nil {
+		return nil, err
+	}
+	return s.client.GetSnapshot(dateISO)
+}
+
+// minister answers GET /minister?date=YYYY-MM-DD&(id=|name=) with every minister matching on the date.
+// Ministers are created per president, so a name can match several ministers.
+func (s *Server) minister(r *http.Request) (interface{}, error) {
+	dateISO, err := dateParam(r, "date")
+	if err != nil {
+		return nil, err
+	}
+	id, name, err := idOrName(r)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.client.GetSnapshot(dateISO)
+	if err != nil {
+		return nil, err
+	}
+
+	var views []models.MinisterView
+	for _, president := range snapshot.Government.Children {
+		for _, minister := range president.Children {
+			if minister.Type != "minister" || (id != "" && minister.ID != id) || (name != "" && minister.Name != name) {
+				continue
+			}
+			views = append(views, models.MinisterView{Date: dateISO, President: president.Name, Minister: minister})
+		}
+	}
+	if len(views) == 0 {
+		if name != "" {
+			return nil, notFound("no minister named '%s' on %s", name, dateISO[:10])
+		}
+		return nil, notFound("no minister %s on %s", id, dateISO[:10])
+	}
+	return views, nil
+}
+
+// person answers GET /person?(id=|name=) with the timeline of the person
+func (s *Server) person(r *http.Request) (interface{}, error) {
+	id, name, err := idOrName(r)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		return s.client.GetPersonTimeline(id)
+	}
+	return s.client.GetPersonTimelineByName(name)
+}
+
+// lineage answers GET /lineage?(id=|name=), with every lineage of the name
+func (s *Server) lineage(r *http.Request) (interface{}, error) {
+	id, name, err := idOrName(r)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		lineage, err := s.client.GetLineage(id)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Lineage{*lineage}, nil
+	}
+	return s.client.GetLineageByName(name)
+}
+
+// diff answers GET /diff?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (s *Server) diff(r *http.Request) (interface{}, error) {
+	fromISO, err := dateParam(r, "from")
+	if err != nil {
+		return nil, err
+	}
+	toISO, err := dateParam(r, "to")
+	if err != nil {
+		return nil, err
+	}
+	if toISO <= fromISO {
+		return nil, badRequest("to must be after from")
+	}
+	return s.client.GetDiff(fromISO, toISO)
+}
@@ -1,7 +1,12 @@
 package tests
 
 import (
+	"fmt"
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/models"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TODO: Please add more tests cases when we cover other angels about gazette tracking. 
@@ -65,3 +70,237 @@ func TestAddDocumentEntity(t *testing.T) {
 		})
 	}
 }
+
+func TestSourcedFromDocument(t *testing.T) {
+	_, err := client.AddDocumentEntity(map[string]interface{}{
+		"transaction_id": "2175-01",
+		"date":           "2025-05-01",
+		"url":            "",
+		"description":    "Ranil Wickremesinghe",
+		"child_type":     "extgztorg",
+		"child":          "2175-01",
+		"parent_type":    "government",
+		"parent":         "Government of Sri Lanka",
+	}, map[string]int{"document": 0})
+	assert.NoError(t, err)
+
+	// A minister added from gazette 2175-01 is linked to its document
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Gazette Sources",
+		"date":           "2025-05-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2175-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"minister": 0})
+	assert.NoError(t, err)
+
+	minister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Gazette Sources", "2025-05-01T00:00:00Z")
+	assert.NoError(t, err)
+
+	sources, err := client.GetSourceDocuments(minister.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sources, 1)
+	if len(sources) == 1 {
+		assert.Equal(t, "2025-05-01T00:00:00Z", sources[0].StartTime)
+	}
+
+	// A transaction without a loaded gazette is still applied
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Unsourced Affairs",
+		"date":           "2025-05-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2175-99_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"minister": 1})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "2156-15", api.GazetteNumberFromTransactionID("2156/15_tr_01"))
+	assert.Equal(t, "", api.GazetteNumberFromTransactionID("2156-15"))
+}
+
+func TestDocumentMetadata(t *testing.T) {
+	transaction := map[string]interface{}{
+		"transaction_id": "2176-02",
+		"date":           "2025-05-10",
+		"url":            "https://documents.gov.lk/gazette/2176-02.pdf",
+		"description":    "Assignment of subjects and functions",
+		"child_type":     "extgztorg",
+		"child":          "2176-02",
+		"parent_type":    "government",
+		"parent":         "Government of Sri Lanka",
+		"part":           "I",
+		"language":       "EN",
+		"pages":          "12",
+	}
+	_, err := client.AddDocumentEntity(transaction, map[string]int{"document": 0})
+	assert.NoError(t, err)
+
+	documents, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{Major: "Document"},
+		Name: "2176-02",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, documents, 1)
+	if len(documents) != 1 {
+		return
+	}
+
+	document, err := client.GetDocumentMetadata(documents[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "2176-02", document.GazetteNumber)
+	assert.Equal(t, "https://documents.gov.lk/gazette/2176-02.pdf", document.URL)
+	assert.Equal(t, "Assignment of subjects and functions", document.Description)
+	assert.Equal(t, "I", document.Part)
+	assert.Equal(t, "en", document.Language)
+	assert.Equal(t, 12, document.Pages)
+
+	// Ingesting the document again updates its metadata without adding a second document
+	transaction["description"] = "Assignment of subjects and functions (corrected)"
+	_, err = client.AddDocumentEntity(transaction, map[string]int{"document": 1})
+	assert.NoError(t, err)
+
+	documents, err = client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{Major: "Document"},
+		Name: "2176-02",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, documents, 1)
+
+	document, err = client.GetDocumentMetadata(documents[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Assignment of subjects and functions (corrected)", document.Description)
+
+	// A page count must be a positive number
+	transaction["pages"] = "twelve"
+	_, err = client.AddDocumentEntity(transaction, map[string]int{"document": 1})
+	assert.Error(t, err)
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	for i, gazette := range []string{"2177-01", "2177-02", "2177-03", "2177-04"} {
+		_, err := client.AddDocumentEntity(map[string]interface{}{
+			"transaction_id": gazette,
+			"date":           fmt.Sprintf("2025-06-%02d", i+1),
+			"url":            "",
+			"description":    "Ranil Wickremesinghe",
+			"child_type":     "extgztorg",
+			"child":          gazette,
+			"parent_type":    "government",
+			"parent":         "Government of Sri Lanka",
+		}, map[string]int{"document": i})
+		assert.NoError(t, err)
+	}
+
+	// 2177-02 amends 2177-01, 2177-03 corrects the amendment and 2177-04 later supersedes 2177-01
+	lifecycle := []struct {
+		fileType string
+		document string
+		target   string
+		date     string
+	}{
+		{"AMENDS", "2177-02", "2177-01", "2025-06-10"},
+		{"CORRECTS", "2177-03", "2177-02", "2025-06-12"},
+		{"SUPERSEDES", "2177-04", "2177-01", "2025-07-01"},
+	}
+	for i, l := range lifecycle {
+		err := client.AddDocumentLifecycle(map[string]interface{}{
+			"transaction_id": fmt.Sprintf("2177-lifecycle_tr_%02d", i+1),
+			"document":       l.document,
+			"target":         l.target,
+			"date":           l.date,
+			"file_type":      l.fileType,
+		})
+		assert.NoError(t, err)
+	}
+
+	// Before the supersession the gazette is in force with its amendment and the correction
+	chain, err := client.GetDocumentsInForce("2177-01", "2025-06-20T00:00:00Z")
+	assert.NoError(t, err)
+	var gazettes []string
+	for _, document := range chain {
+		gazettes = append(gazettes, document.GazetteNumber)
+	}
+	assert.Equal(t, []string{"2177-01", "2177-02", "2177-03"}, gazettes)
+	if len(chain) == 3 {
+		assert.Equal(t, api.CorrectsRelType, chain[2].Relationship)
+		assert.Equal(t, "2177-02", chain[2].Target)
+	}
+
+	// After it only the superseding gazette is in force
+	chain, err = client.GetDocumentsInForce("2177-01", "2025-07-15T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Len(t, chain, 1)
+	if len(chain) == 1 {
+		assert.Equal(t, "2177-04", chain[0].GazetteNumber)
+		assert.Equal(t, api.SupersedesRelType, chain[0].Relationship)
+	}
+
+	// A supersession cycle is rejected
+	err = client.AddDocumentLifecycle(map[string]interface{}{
+		"transaction_id": "2177-lifecycle_tr_04",
+		"document":       "2177-01",
+		"target":         "2177-04",
+		"date":           "2025-08-01",
+		"file_type":      "SUPERSEDES",
+	})
+	assert.Error(t, err)
+}
+
+func TestAttachDocumentToPeopleAndMinisters(t *testing.T) {
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Gazette Records",
+		"date":           "2025-06-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2178-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"minister": 0})
+	assert.NoError(t, err)
+
+	// One gazette concerning the president and a minister
+	transaction := map[string]interface{}{
+		"transaction_id": "2178-02",
+		"date":           "2025-06-05",
+		"url":            "",
+		"description":    "Ranil Wickremesinghe",
+		"child_type":     "extgztperson",
+		"child":          "2178-02",
+		"parent_type":    "[president; minister]",
+		"parent":         "[Ranil Wickremesinghe; Minister of Gazette Records]",
+		"president":      "Ranil Wickremesinghe",
+	}
+	_, err = client.AddDocumentEntity(transaction, map[string]int{"document": 0})
+	assert.NoError(t, err)
+
+	// Loading the row again does not link the document twice
+	_, err = client.AddDocumentEntity(transaction, map[string]int{"document": 1})
+	assert.NoError(t, err)
+
+	president, err := client.GetPresidentByGovernment("Ranil Wickremesinghe")
+	assert.NoError(t, err)
+	minister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Gazette Records", "2025-06-05T00:00:00Z")
+	assert.NoError(t, err)
+
+	for _, parentID := range []string{president.ID, minister.ID} {
+		relations, err := client.GetRelatedEntities(parentID, &models.Relationship{
+			Name:      "AS_DOCUMENT",
+			Direction: "OUTGOING",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, relations, 1)
+	}
+
+	// The number of parent types must match the parents
+	transaction["child"] = "2178-03"
+	transaction["parent_type"] = "[president; minister; department]"
+	_, err = client.AddDocumentEntity(transaction, map[string]int{"document": 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,350 @@
+package tests
+
+import (
+	"bytes"
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGraph(t *testing.T) {
+	// A classified minister and a person with an additional attribute keep their attributes when exported
+	entityCounters := map[string]int{"minister": 0, "citizen": 0}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":           "Ranil Wickremesinghe",
+		"child":            "State Minister of Exported Records",
+		"date":             "2026-02-01",
+		"parent_type":      "citizen",
+		"child_type":       "minister",
+		"rel_type":         "AS_MINISTER",
+		"gazette_position": "7",
+		"transaction_id":   "2189-01_tr_01",
+		"president":        "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":            "State Minister of Exported Records",
+		"child":             "Exported Tester",
+		"date":              "2026-02-01",
+		"parent_type":       "minister",
+		"child_type":        "citizen",
+		"rel_type":          "AS_STATE_MINISTER",
+		"party":             "UNP",
+		"attr_constituency": "Colombo",
+		"transaction_id":    "2189-01_tr_02",
+		"president":         "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	graph, err := client.GetGraph()
+	assert.NoError(t, err)
+	assert.NoError(t, api.ValidateGraph(graph))
+
+	var minister, person *models.GraphEntity
+	for i := range graph.Entities {
+		switch graph.Entities[i].Name {
+		case "State Minister of Exported Records":
+			minister = &graph.Entities[i]
+		case "Exported Tester":
+			person = &graph.Entities[i]
+		}
+	}
+	if assert.NotNil(t, minister) && assert.NotNil(t, person) {
+		if assert.Len(t, minister.Attributes[api.MinisterCategoryAttribute], 1) {
+			assert.Equal(t, api.MinisterCategoryState, minister.Attributes[api.MinisterCategoryAttribute][0].Value)
+		}
+		if assert.Len(t, minister.Attributes[api.MinisterGazettePositionAttribute], 1) {
+			assert.EqualValues(t, 7, minister.Attributes[api.MinisterGazettePositionAttribute][0].Value)
+		}
+		if assert.Len(t, person.Attributes["constituency"], 1) {
+			assert.Equal(t, "Colombo", person.Attributes["constituency"][0].Value)
+		}
+		assert.Len(t, person.Attributes["party"], 1)
+
+		// The exported file reads back with the same attributes
+		var buf bytes.Buffer
+		assert.NoError(t, export.WriteGraphJSON(&buf, graph))
+		read, err := export.ReadGraphJSON(&buf)
+		assert.NoError(t, err)
+		for _, entity := range read.Entities {
+			switch entity.ID {
+			case minister.ID:
+				assert.Equal(t, *minister, entity)
+			case person.ID:
+				assert.Equal(t, *person, entity)
+			}
+		}
+	}
+
+	var government *models.GraphEntity
+	for i := range graph.Entities {
+		if graph.Entities[i].Kind.Minor == "government" {
+			government = &graph.Entities[i]
+		}
+	}
+	if assert.NotNil(t, government) {
+		assert.Equal(t, "Government of Sri Lanka", government.Name)
+	}
+
+	presidents := 0
+	for _, rel := range graph.Relationships {
+		if rel.Name == "AS_PRESIDENT" {
+			assert.Equal(t, government.ID, rel.From)
+			presidents++
+		}
+	}
+	assert.Greater(t, presidents, 0)
+}
+
+func TestGraphFiles(t *testing.T) {
+	graph := &models.Graph{
+		ExportedAt: "2025-01-01T00:00:00Z",
+		Entities: []models.GraphEntity{
+			{ID: "gov_01", Kind: models.Kind{Major: "Organisation", Minor: "government"}, Name: "Government of Sri Lanka", Created: "1978-09-07T00:00:00Z"},
+			{
+				ID: "cit_1", Kind: models.Kind{Major: "Person", Minor: "citizen"}, Name: "Ranil O'Wickremesinghe", Created: "2022-07-21T00:00:00Z",
+				Attributes: map[string][]models.TimeBasedValue{"party": {{StartTime: "2022-07-21T00:00:00Z", Value: "UNP"}}},
+			},
+			{
+				ID: "doc_1", Kind: models.Kind{Major: "Document", Minor: "extraordinary_gazette"}, Name: "2289-43", Created: "2022-07-22T00:00:00Z",
+				Metadata: map[string]interface{}{"gazette_number": "2289/43", "url": "https://example.org/2289-43.pdf"},
+			},
+		},
+		Relationships: []models.GraphRelationship{
+			{ID: "rel_1", Name: "AS_PRESIDENT", From: "gov_01", To: "cit_1", StartTime: "2022-07-21T00:00:00Z"},
+			{ID: "rel_2", Name: "AS_DOCUMENT", From: "cit_1", To: "doc_1", StartTime: "2022-07-22T00:00:00Z", EndTime: "2024-09-23T00:00:00Z"},
+		},
+	}
+	assert.NoError(t, api.ValidateGraph(graph))
+
+	// CSV files read back into the same graph
+	var entities, relationships bytes.Buffer
+	assert.NoError(t, export.WriteGraphCSV(&entities, &relationships, graph))
+	assert.Contains(t, entities.String(), "gov_01,Entity;Organisation,government,Government of Sri Lanka,1978-09-07T00:00:00Z,,,")
+	assert.Contains(t, relationships.String(), "rel_2,cit_1,doc_1,AS_DOCUMENT,2022-07-22T00:00:00Z,2024-09-23T00:00:00Z")
+
+	read, err := export.ReadGraphCSV(&entities, &relationships)
+	assert.NoError(t, err)
+	assert.Equal(t, graph.Entities, read.Entities)
+	assert.Equal(t, graph.Relationships, read.Relationships)
+
+	// JSON reads back as well
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteGraphJSON(&buf, graph))
+	read, err = export.ReadGraphJSON(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, graph, read)
+
+	// Cypher creates every node before matching them for relationships
+	var cypher bytes.Buffer
+	assert.NoError(t, export.WriteGraphCypher(&cypher, graph))
+	assert.Contains(t, cypher.String(), "CREATE (:Entity:`Person` {id: 'cit_1', kind_minor: 'citizen', name: 'Ranil O\\'Wickremesinghe', created: '2022-07-21T00:00:00Z', attributes: '{\"party\":[{\"startTime\":\"2022-07-21T00:00:00Z\",\"value\":\"UNP\"}]}'});")
+	assert.Contains(t, cypher.String(), "MATCH (a:Entity {id: 'gov_01'}), (b:Entity {id: 'cit_1'}) CREATE (a)-[:`AS_PRESIDENT` {id: 'rel_1', startTime: '2022-07-21T00:00:00Z'}]->(b);")
+
+	// Relationships must link exported entities
+	graph.Relationships = append(graph.Relationships, models.GraphRelationship{ID: "rel_3", Name: "AS_MINISTER", From: "cit_1", To: "min_missing"})
+	assert.Error(t, api.ValidateGraph(graph))
+}
+
+func TestComputeStats(t *testing.T) {
+	entity := func(id, major, minor, name string) models.GraphEntity {
+		return models.GraphEntity{ID: id, Kind: models.Kind{Major: major, Minor: minor}, Name: name}
+	}
+	relationship := func(id, name, from, to, start, end string) models.GraphRelationship {
+		return models.GraphRelationship{ID: id, Name: name, From: from, To: to, StartTime: start + "T00:00:00Z", EndTime: end}
+	}
+	graph := &models.Graph{
+		ExportedAt: "2023-06-01T00:00:00Z",
+		Entities: []models.GraphEntity{
+			entity("gov_01", "Organisation", "government", "Government of Sri Lanka"),
+			entity("rw", "Person", "citizen", "Ranil Wickremesinghe"),
+			entity("min_a", "Organisation", "minister", "Minister of A"),
+			entity("min_b", "Organisation", "minister", "Minister of A and B"),
+			entity("min_c", "Organisation", "minister", "Minister of C"),
+			entity("dep_d", "Organisation", "department", "Department of D"),
+			entity("p", "Person", "citizen", "Person P"),
+			entity("q", "Person", "citizen", "Person Q"),
+		},
+		Relationships: []models.GraphRelationship{
+			relationship("r01", "AS_PRESIDENT", "gov_01", "rw", "2022-07-21", ""),
+			relationship("r02", "AS_MINISTER", "rw", "min_a", "2022-07-22", "2022-10-01T00:00:00Z"),
+			relationship("r03", "RENAMED_TO", "min_a", "min_b", "2022-10-01", ""),
+			relationship("r04", "AS_MINISTER", "rw", "min_b", "2022-10-01", ""),
+			relationship("r05", "AS_MINISTER", "rw", "min_c", "2022-08-01", "2023-02-01T00:00:00Z"),
+			relationship("r06", "AS_DEPARTMENT", "min_a", "dep_d", "2022-07-22", "2022-10-01T00:00:00Z"),
+			relationship("r07", "AS_DEPARTMENT", "min_b", "dep_d", "2022-10-01", "2023-01-15T00:00:00Z"),
+			relationship("r08", "AS_DEPARTMENT", "min_c", "dep_d", "2023-01-15", "2023-02-01T00:00:00Z"),
+			relationship("r09", "AS_APPOINTED", "min_a", "p", "2022-07-22", "2022-10-01T00:00:00Z"),
+			relationship("r10", "AS_APPOINTED", "min_b", "p", "2022-10-01", "2023-03-01T00:00:00Z"),
+			relationship("r11", "AS_APPOINTED", "min_c", "q", "2022-08-01", "2023-01-10T00:00:00Z"),
+			relationship("r12", "AS_STATE_MINISTER", "min_b", "q", "2023-01-10", ""),
+		},
+	}
+
+	stats := api.ComputeStats(graph)
+	if assert.Len(t, stats, 2) {
+		first := stats[0]
+		assert.Equal(t, "Ranil Wickremesinghe", first.President)
+		assert.Equal(t, 2022, first.Year)
+		assert.Equal(t, "2022-07-21T00:00:00Z", first.From)
+		assert.Equal(t, "2023-01-01T00:00:00Z", first.To)
+		assert.Equal(t, 3, first.ActiveMinisters)
+		assert.Equal(t, 1, first.ActiveDepartments)
+		assert.Equal(t, 2, first.MinistersCreated)
+		assert.Equal(t, 1, first.MinistersRenamed)
+		assert.Equal(t, 0, first.MinistersTerminated)
+		assert.Equal(t, 1, first.PortfoliosEnded)
+		assert.Equal(t, 71.0, first.AveragePortfolioDays)
+		assert.Equal(t, 2, first.PeopleAppointed)
+		assert.Equal(t, 0, first.PeopleRemoved)
+		assert.Equal(t, 0, first.DepartmentsMoved)
+
+		second := stats[1]
+		assert.Equal(t, 2023, second.Year)
+		assert.Equal(t, "2023-06-01T00:00:00Z", second.To)
+		assert.Equal(t, 2, second.ActiveMinisters)
+		assert.Equal(t, 1, second.MinistersTerminated)
+		assert.Equal(t, 184.0, second.AveragePortfolioDays)
+		assert.Equal(t, 0, second.PeopleAppointed)
+		assert.Equal(t, 1, second.PeopleRemoved)
+		assert.Equal(t, 1, second.PeopleMoved)
+		assert.Equal(t, 1, second.DepartmentsMoved)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, export.WriteStatsCSV(&buf, stats))
+	assert.Contains(t, buf.String(), "Ranil Wickremesinghe,2023,2023-01-01,2023-06-01,2,1,0,0,0,0,1,1,184.0,0,1,1,1")
+}
+
+func TestBuildTransactions(t *testing.T) {
+	entity := func(id, major, minor, name string) models.GraphEntity {
+		return models.GraphEntity{ID: id, Kind: models.Kind{Major: major, Minor: minor}, Name: name}
+	}
+	relationship := func(id, name, from, to, start, end string) models.GraphRelationship {
+		if end != "" {
+			end += "T00:00:00Z"
+		}
+		return models.GraphRelationship{ID: id, Name: name, From: from, To: to, StartTime: start + "T00:00:00Z", EndTime: end}
+	}
+	graph := &models.Graph{
+		Entities: []models.GraphEntity{
+			entity("gov_01", "Organisation", "government", "Government of Sri Lanka"),
+			entity("rw", "Person", "citizen", "Ranil Wickremesinghe"),
+			entity("min_a", "Organisation", "minister", "Minister of A"),
+			{ID: "min_b", Kind: models.Kind{Major: "Organisation", Minor: "minister"}, Name: "Minister of A and B",
+				Created: "2022-10-01T00:00:00Z", Attributes: map[string][]models.TimeBasedValue{
+					"category": {{StartTime: "2022-10-01T00:00:00Z", Value: "cabinet"}},
+				}},
+			{ID: "min_c", Kind: models.Kind{Major: "Organisation", Minor: "minister"}, Name: "Minister of C",
+				Created: "2022-08-01T00:00:00Z", Attributes: map[string][]models.TimeBasedValue{
+					"category":         {{StartTime: "2022-08-01T00:00:00Z", Value: "cabinet"}},
+					"gazette_position": {{StartTime: "2022-08-01T00:00:00Z", Value: float64(3)}},
+				}},
+			entity("dep_d", "Organisation", "department", "Department of D"),
+			entity("p", "Person", "citizen", "Person P"),
+			entity("q", "Person", "citizen", "Person Q"),
+			{ID: "r", Kind: models.Kind{Major: "Person", Minor: "citizen"}, Name: "Person R",
+				Created: "2022-08-01T00:00:00Z", Attributes: map[string][]models.TimeBasedValue{
+					"party": {
+						{StartTime: "2022-08-01T00:00:00Z", Value: "UNP"},
+						{StartTime: "2023-01-01T00:00:00Z", Value: "SJB"},
+					},
+					"constituency": {{StartTime: "2022-08-01T00:00:00Z", Value: "Colombo"}},
+					"honorific":    {{StartTime: "2022-09-01T00:00:00Z", EndTime: "2023-01-01T00:00:00Z", Value: "Hon."}},
+				}},
+			{ID: "doc", Kind: models.Kind{Major: "Document", Minor: "extraordinary_gazette"}, Name: "2290-01",
+				Metadata: map[string]interface{}{"url": "https://example.org/2290-01.pdf", "pages": 4}},
+			entity("doc_2", "Document", "extraordinary_gazette", "2301-05"),
+		},
+		Relationships: []models.GraphRelationship{
+			relationship("r01", "AS_PRESIDENT", "gov_01", "rw", "2022-07-21", ""),
+			relationship("r02", "AS_MINISTER", "rw", "min_a", "2022-07-22", "2022-10-01"),
+			relationship("r03", "RENAMED_TO", "min_a", "min_b", "2022-10-01", ""),
+			relationship("r04", "AS_MINISTER", "rw", "min_b", "2022-10-01", ""),
+			relationship("r05", "AS_MINISTER", "rw", "min_c", "2022-08-01", "2023-02-01"),
+			relationship("r06", "AS_DEPARTMENT", "min_a", "dep_d", "2022-07-22", "2022-10-01"),
+			relationship("r07", "AS_DEPARTMENT", "min_b", "dep_d", "2022-10-01", "2023-01-15"),
+			relationship("r08", "AS_DEPARTMENT", "min_c", "dep_d", "2023-01-15", "2023-02-01"),
+			relationship("r09", "AS_APPOINTED", "min_a", "p", "2022-07-22", "2022-10-01"),
+			relationship("r10", "AS_APPOINTED", "min_b", "p", "2022-10-01", "2023-03-01"),
+			relationship("r11", "AS_APPOINTED", "min_c", "q", "2022-08-01", "2023-01-10"),
+			relationship("r12", "AS_STATE_MINISTER", "min_b", "q", "2023-01-10", ""),
+			relationship("r13", "AS_APPOINTED", "min_c", "r", "2022-08-01", "2023-02-01"),
+			relationship("r14", "SOURCED_FROM", "min_a", "doc", "2022-07-22", ""),
+			relationship("r15", "SOURCED_FROM", "dep_d", "doc", "2022-07-22", ""),
+			relationship("r16", "AS_DOCUMENT", "gov_01", "doc", "2022-07-22", ""),
+			relationship("r17", "AS_DOCUMENT", "min_b", "doc_2", "2022-10-01", ""),
+			relationship("r18", "AMENDS", "doc_2", "doc", "2022-09-15", ""),
+		},
+	}
+
+	// Every directory as path, then its files as type and CSV rows
+	render := func(directories []models.TransactionDirectory) []string {
+		var lines []string
+		for _, directory := range directories {
+			lines = append(lines, directory.Path+" ("+directory.ProcessType+")")
+			for _, file := range directory.Files {
+				var buf bytes.Buffer
+				assert.NoError(t, export.WriteTransactionCSV(&buf, file))
+				rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				for _, row := range rows[1:] {
+					lines = append(lines, "  "+file.Type+": "+row)
+				}
+			}
+		}
+		return lines
+	}
+
+	assert.Equal(t, []string{
+		"people/Ranil Wickremesinghe/2022-07-21/presidency (person)",
+		"  ADD: 2022-07-21_tr_01,Government of Sri Lanka,government,Ranil Wickremesinghe,citizen,AS_PRESIDENT,2022-07-21",
+		"documents/Ranil Wickremesinghe/2022-07-22 (document)",
+		"  ADD: 2022-07-22_tr_01,2022-07-22,extraordinary_gazette,2290-01,government,Government of Sri Lanka,https://example.org/2290-01.pdf,,,,,4",
+		"orgchart/Ranil Wickremesinghe/2022-07-22 (organisation)",
+		"  ADD: 2290-01_tr_01,Ranil Wickremesinghe,citizen,Minister of A,minister,AS_MINISTER,2022-07-22",
+		"  ADD: 2290-01_tr_02,Minister of A,minister,Department of D,department,AS_DEPARTMENT,2022-07-22",
+		"people/Ranil Wickremesinghe/2022-07-22 (person)",
+		"  ADD: 2022-07-22_tr_01,Minister of A,minister,Person P,citizen,AS_APPOINTED,2022-07-22",
+		"orgchart/Ranil Wickremesinghe/2022-08-01 (organisation)",
+		"  ADD: 2022-08-01_tr_01,Ranil Wickremesinghe,citizen,Minister of C,minister,AS_MINISTER,2022-08-01,cabinet,3",
+		"people/Ranil Wickremesinghe/2022-08-01 (person)",
+		"  ADD: 2022-08-01_tr_01,Minister of C,minister,Person Q,citizen,AS_APPOINTED,2022-08-01,,",
+		"  ADD: 2022-08-01_tr_02,Minister of C,minister,Person R,citizen,AS_APPOINTED,2022-08-01,UNP,Colombo",
+		"people/Ranil Wickremesinghe/2022-09-01 (person)",
+		"  ATTRIBUTE: 2022-09-01_tr_01,Person R,honorific,Hon.,2022-09-01,2023-01-01",
+		"orgchart/Ranil Wickremesinghe/2022-10-01 (organisation)",
+		"  RENAME: 2022-10-01_tr_01,Minister of A,Minister of A and B,minister,2022-10-01,cabinet",
+		"documents/Ranil Wickremesinghe/2022-10-01/links (document)",
+		"  ADD: 2022-10-01_tr_01,2022-10-01,extraordinary_gazette,2301-05,minister,Minister of A and B,,,,,,",
+		"  AMENDS: 2022-10-01_tr_02,2301-05,2290-01,2022-09-15",
+		"people/Ranil Wickremesinghe/2023-01-01 (person)",
+		"  ATTRIBUTE: 2023-01-01_tr_01,Person R,party,SJB,2023-01-01,",
+		"people/Ranil Wickremesinghe/2023-01-10 (person)",
+		"  MOVE: 2023-01-10_tr_01,Minister of C,minister,Ranil Wickremesinghe,Minister of A and B,minister,Ranil Wickremesinghe,Person Q,citizen,state_minister,cabinet_minister,2023-01-10",
+		"orgchart/Ranil Wickremesinghe/2023-01-15 (organisation)",
+		"  MOVE: 2023-01-15_tr_01,Minister of A and B,Minister of C,Department of D,department,2023-01-15,Ranil Wickremesinghe,Ranil Wickremesinghe,minister,",
+		"orgchart/Ranil Wickremesinghe/2023-02-01 (organisation)",
+		"  TERMINATE: 2023-02-01_tr_01,Minister of C,minister,Department of D,department,AS_DEPARTMENT,2023-02-01",
+		"  TERMINATE: 2023-02-01_tr_02,Ranil Wickremesinghe,citizen,Minister of C,minister,AS_MINISTER,2023-02-01",
+		"people/Ranil Wickremesinghe/2023-03-01 (person)",
+		"  TERMINATE: 2023-03-01_tr_01,Minister of A and B,minister,Person P,citizen,AS_APPOINTED,2023-03-01",
+	}, render(api.BuildTransactions(graph, "")))
+
+	// Up to a date, later changes are left out and later ends are open
+	directories := api.BuildTransactions(graph, "2022-09-01T00:00:00Z")
+	if assert.Len(t, directories, 7) {
+		assert.Equal(t, "people/Ranil Wickremesinghe/2022-08-01", directories[5].Path)
+		assert.Equal(t, "documents_ADD.csv", directories[1].Files[0].Name)
+		assert.Equal(t, [][]string{{"2022-09-01_tr_01", "Person R", "honorific", "Hon.", "2022-09-01", ""}}, directories[6].Files[0].Rows)
+	}
+
+	var script bytes.Buffer
+	assert.NoError(t, export.WriteLoadScript(&script, directories))
+	assert.Contains(t, script.String(), "./orgchart -data \"$DATA/people/Ranil Wickremesinghe/2022-07-21/presidency/\" -type person -init || exit 1\n")
+	assert.Contains(t, script.String(), "./orgchart -data \"$DATA/documents/Ranil Wickremesinghe/2022-07-22/\" -type document || exit 1\n")
+	assert.Contains(t, script.String(), "./orgchart -data \"$DATA/orgchart/Ranil Wickremesinghe/2022-07-22/\" || exit 1\n")
+}
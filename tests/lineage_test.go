@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineage(t *testing.T) {
+	entityCounters := map[string]int{"minister": 0, "department": 0}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Lineage Affairs",
+		"date":           "2025-10-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2182-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Lineage Affairs",
+		"child":          "Department of Lineage Records",
+		"date":           "2025-10-01",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2182-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	// Renamed and renamed back: the second rename reuses the inactive department
+	for _, rename := range []map[string]interface{}{
+		{"old": "Department of Lineage Records", "new": "Department of Lineage Archives", "date": "2025-10-05", "transaction_id": "2182-02_tr_01"},
+		{"old": "Department of Lineage Archives", "new": "Department of Lineage Records", "date": "2025-10-10", "transaction_id": "2182-03_tr_01"},
+	} {
+		rename["type"] = "department"
+		rename["president"] = "Ranil Wickremesinghe"
+		_, err = client.RenameDepartment(rename, entityCounters)
+		assert.NoError(t, err)
+	}
+
+	lineages, err := client.GetLineageByName("Department of Lineage Records")
+	assert.NoError(t, err)
+	if assert.Len(t, lineages, 1) {
+		lineage := lineages[0]
+		if assert.Len(t, lineage.Descendants, 2) {
+			assert.Equal(t, api.ChangeRenamed, lineage.Descendants[0].Action)
+			assert.Equal(t, "Department of Lineage Archives", lineage.Descendants[0].ToName)
+			assert.Equal(t, "2025-10-05T00:00:00Z", lineage.Descendants[0].Date)
+			assert.Equal(t, "Ranil Wickremesinghe", lineage.Descendants[0].President)
+			assert.Equal(t, "Department of Lineage Records", lineage.Descendants[1].ToName)
+			assert.Equal(t, "2025-10-10T00:00:00Z", lineage.Descendants[1].Date)
+		}
+		assert.Len(t, lineage.Ancestors, 2)
+
+		// The reused department was held twice
+		assert.Len(t, lineage.Entities, 2)
+		for _, entity := range lineage.Entities {
+			if entity.Name == "Department of Lineage Records" {
+				if assert.Len(t, entity.Periods, 2) {
+					assert.Equal(t, "2025-10-05T00:00:00Z", entity.Periods[0].EndTime)
+					assert.Equal(t, "2025-10-10T00:00:00Z", entity.Periods[1].StartTime)
+					assert.Equal(t, "Minister of Lineage Affairs", entity.Periods[1].Parent)
+					assert.Equal(t, "Ranil Wickremesinghe", entity.Periods[1].President)
+				}
+			}
+		}
+	}
+
+	_, err = client.GetLineageByName("Department of Nothing At All")
+	assert.Error(t, err)
+}
+
+func TestDescribeLineageEvent(t *testing.T) {
+	event := models.LineageEvent{
+		Action:    api.ChangeMerged,
+		FromName:  "Minister of A",
+		ToName:    "Minister of A and B",
+		Date:      "2022-07-22T00:00:00Z",
+		President: "Ranil Wickremesinghe",
+		Gazettes:  []string{"2289-43"},
+	}
+	assert.Equal(t, "2022-07-22 Minister of A merged into Minister of A and B (Ranil Wickremesinghe; gazette 2289-43)", export.DescribeLineageEvent(event))
+
+	event.Action = api.ChangeRenamed
+	event.President = ""
+	event.Gazettes = nil
+	assert.Equal(t, "2022-07-22 Minister of A renamed to Minister of A and B", export.DescribeLineageEvent(event))
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"errors"
 	"fmt"
 	"orgchart_nexoan/api"
 	"orgchart_nexoan/models"
@@ -1068,3 +1069,1183 @@ func TestCreateDuplicateMinister(t *testing.T) {
 	assert.NotEqual(t, results[0].ID, results[1].ID, "Ministers should have different IDs")
 
 }
+
+func TestMergeDepartments(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	departmentCounters := map[string]int{
+		"department": 0,
+	}
+
+	// Create a minister holding two departments that will be merged
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Ports and Shipping",
+		"date":           "2025-02-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2160-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	for i, department := range []string{"Department of Ports", "Department of Shipping"} {
+		departmentCounters["department"], err = client.AddOrgEntity(map[string]interface{}{
+			"parent":         "Minister of Ports and Shipping",
+			"child":          department,
+			"date":           "2025-02-01",
+			"parent_type":    "minister",
+			"child_type":     "department",
+			"rel_type":       "AS_DEPARTMENT",
+			"transaction_id": fmt.Sprintf("2160-01_tr_%02d", i+2),
+			"president":      "Ranil Wickremesinghe",
+		}, departmentCounters)
+		assert.NoError(t, err)
+	}
+
+	// Merge the departments into a new department
+	transaction := map[string]interface{}{
+		"old":            "[Department of Ports; Department of Shipping]",
+		"new":            "Department of Ports and Shipping",
+		"type":           "department",
+		"date":           "2025-02-10",
+		"transaction_id": "2160-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}
+
+	newDepartmentCounter, err := client.MergeDepartments(transaction, departmentCounters)
+	assert.NoError(t, err)
+	assert.Greater(t, newDepartmentCounter, 0)
+
+	// Verify the new department is active under the minister
+	ministerResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "minister",
+		},
+		Name: "Minister of Ports and Shipping",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ministerResults, 1)
+	ministerID := ministerResults[0].ID
+
+	newDepartmentResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "department",
+		},
+		Name: "Department of Ports and Shipping",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, newDepartmentResults, 1)
+	newDepartmentID := newDepartmentResults[0].ID
+
+	newRelations, err := client.GetRelatedEntities(ministerID, &models.Relationship{
+		RelatedEntityID: newDepartmentID,
+		Name:            "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, newRelations, 1)
+	assert.Equal(t, "", newRelations[0].EndTime)
+	assert.Equal(t, "2025-02-10T00:00:00Z", newRelations[0].StartTime)
+
+	// Verify the old departments are terminated and merged into the new department
+	for _, oldDepartment := range []string{"Department of Ports", "Department of Shipping"} {
+		oldDepartmentResults, err := client.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: "department",
+			},
+			Name: oldDepartment,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, oldDepartmentResults, 1)
+		oldDepartmentID := oldDepartmentResults[0].ID
+
+		oldRelations, err := client.GetRelatedEntities(ministerID, &models.Relationship{
+			RelatedEntityID: oldDepartmentID,
+			Name:            "AS_DEPARTMENT",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, oldRelations, 1)
+		assert.Equal(t, "2025-02-10T00:00:00Z", oldRelations[0].EndTime)
+
+		mergedRelations, err := client.GetRelatedEntities(oldDepartmentID, &models.Relationship{
+			RelatedEntityID: newDepartmentID,
+			Name:            "MERGED_INTO",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, mergedRelations, 1)
+		assert.Equal(t, "2025-02-10T00:00:00Z", mergedRelations[0].StartTime)
+	}
+}
+
+func TestSplitMinister(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	departmentCounters := map[string]int{
+		"department": 0,
+	}
+
+	// Create a minister with two departments that will be divided between two new ministers
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Trade and Tourism",
+		"date":           "2025-03-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2161-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	for i, department := range []string{"Department of Commerce", "Sri Lanka Tourism Development Authority"} {
+		departmentCounters["department"], err = client.AddOrgEntity(map[string]interface{}{
+			"parent":         "Minister of Trade and Tourism",
+			"child":          department,
+			"date":           "2025-03-01",
+			"parent_type":    "minister",
+			"child_type":     "department",
+			"rel_type":       "AS_DEPARTMENT",
+			"transaction_id": fmt.Sprintf("2161-01_tr_%02d", i+2),
+			"president":      "Ranil Wickremesinghe",
+		}, departmentCounters)
+		assert.NoError(t, err)
+	}
+
+	// Split the minister into two new ministers
+	transaction := map[string]interface{}{
+		"old":            "Minister of Trade and Tourism",
+		"new":            "[Minister of Trade; Minister of Tourism]",
+		"departments":    "[Department of Commerce=Minister of Trade; Sri Lanka Tourism Development Authority=Minister of Tourism]",
+		"appointees":     "terminate",
+		"date":           "2025-03-10",
+		"transaction_id": "2161-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}
+
+	newMinisterCounter, err := client.SplitMinister(transaction, ministerCounters)
+	assert.NoError(t, err)
+	assert.Greater(t, newMinisterCounter, 0)
+
+	oldMinisterResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "minister",
+		},
+		Name: "Minister of Trade and Tourism",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, oldMinisterResults, 1)
+	oldMinisterID := oldMinisterResults[0].ID
+
+	// Verify each department moved to its mapped minister and the SPLIT_INTO relationships exist
+	expected := map[string]string{
+		"Department of Commerce":                  "Minister of Trade",
+		"Sri Lanka Tourism Development Authority": "Minister of Tourism",
+	}
+	for department, minister := range expected {
+		ministerResults, err := client.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: "minister",
+			},
+			Name: minister,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, ministerResults, 1)
+		ministerID := ministerResults[0].ID
+
+		departmentResults, err := client.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: "department",
+			},
+			Name: department,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, departmentResults, 1)
+
+		relations, err := client.GetRelatedEntities(ministerID, &models.Relationship{
+			RelatedEntityID: departmentResults[0].ID,
+			Name:            "AS_DEPARTMENT",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, relations, 1)
+		assert.Equal(t, "", relations[0].EndTime)
+
+		splitRelations, err := client.GetRelatedEntities(oldMinisterID, &models.Relationship{
+			RelatedEntityID: ministerID,
+			Name:            "SPLIT_INTO",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, splitRelations, 1)
+		assert.Equal(t, "2025-03-10T00:00:00Z", splitRelations[0].StartTime)
+	}
+
+	// Verify the old minister has no active departments left
+	oldDeptRelations, err := client.GetRelatedEntities(oldMinisterID, &models.Relationship{Name: "AS_DEPARTMENT"})
+	assert.NoError(t, err)
+	for _, rel := range oldDeptRelations {
+		assert.Equal(t, "2025-03-10T00:00:00Z", rel.EndTime)
+	}
+}
+
+func TestSplitMinisterWithUnmappedDepartment(t *testing.T) {
+	entityCounters := map[string]int{
+		"minister": 0,
+	}
+
+	transaction := map[string]interface{}{
+		"old":            "Minister of Defence",
+		"new":            "[Minister of Army; Minister of Navy]",
+		"departments":    "[Sri Lankan Army=Minister of Army]",
+		"appointees":     "Minister of Air Force",
+		"date":           "2025-03-10",
+		"transaction_id": "2161-03_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}
+
+	// Appointees must go to one of the new ministers
+	_, err := client.SplitMinister(transaction, entityCounters)
+	assert.Error(t, err)
+}
+
+func TestSplitMinisterKeepingName(t *testing.T) {
+	entityCounters := map[string]int{"minister": 0, "department": 0, "citizen": 0}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Fisheries",
+		"date":           "2025-03-15",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2191-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	for i, department := range []string{"Department of Fisheries Research", "Department of Aquaculture"} {
+		_, err = client.AddOrgEntity(map[string]interface{}{
+			"parent":         "Minister of Fisheries",
+			"child":          department,
+			"date":           "2025-03-15",
+			"parent_type":    "minister",
+			"child_type":     "department",
+			"rel_type":       "AS_DEPARTMENT",
+			"transaction_id": fmt.Sprintf("2191-01_tr_%02d", i+2),
+			"president":      "Ranil Wickremesinghe",
+		}, entityCounters)
+		assert.NoError(t, err)
+	}
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Fisheries",
+		"child":          "Split Keeper",
+		"date":           "2025-03-15",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_APPOINTED",
+		"transaction_id": "2191-01_tr_04",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	oldMinister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Fisheries", "2025-03-15T00:00:00Z")
+	assert.NoError(t, err)
+
+	// The minister keeps its name for one part of the split
+	_, err = client.SplitMinister(map[string]interface{}{
+		"old":            "Minister of Fisheries",
+		"new":            "[Minister of Fisheries; Minister of Aquaculture]",
+		"departments":    "[Department of Fisheries Research=Minister of Fisheries; Department of Aquaculture=Minister of Aquaculture]",
+		"appointees":     "Minister of Fisheries",
+		"date":           "2025-03-20",
+		"transaction_id": "2191-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	newMinister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Fisheries", "2025-03-21T00:00:00Z")
+	assert.NoError(t, err)
+	assert.NotEqual(t, oldMinister.ID, newMinister.ID)
+
+	splitRelations, err := client.GetRelatedEntities(oldMinister.ID, &models.Relationship{
+		RelatedEntityID: newMinister.ID,
+		Name:            "SPLIT_INTO",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, splitRelations, 1)
+
+	// The department and the appointee mapped to it are held by the new minister
+	departmentResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "department",
+		},
+		Name: "Department of Fisheries Research",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, departmentResults, 1)
+	departmentRelations, err := client.GetRelatedEntities(newMinister.ID, &models.Relationship{
+		RelatedEntityID: departmentResults[0].ID,
+		Name:            "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, departmentRelations, 1) {
+		assert.Equal(t, "2025-03-20T00:00:00Z", departmentRelations[0].StartTime)
+		assert.Equal(t, "", departmentRelations[0].EndTime)
+	}
+
+	appointments, err := client.GetRelatedEntities(newMinister.ID, &models.Relationship{Name: "AS_APPOINTED"})
+	assert.NoError(t, err)
+	if assert.Len(t, appointments, 1) {
+		assert.Equal(t, "", appointments[0].EndTime)
+	}
+
+	oldDepartments, err := client.GetRelatedEntities(oldMinister.ID, &models.Relationship{Name: "AS_DEPARTMENT"})
+	assert.NoError(t, err)
+	for _, rel := range oldDepartments {
+		assert.Equal(t, "2025-03-20T00:00:00Z", rel.EndTime)
+	}
+}
+
+func TestMoveMinisterReassignAppointees(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	personCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	// Create the incoming president
+	_, err := client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Government of Sri Lanka",
+		"child":          "Maithripala Sirisena",
+		"date":           "2025-04-01",
+		"parent_type":    "government",
+		"child_type":     "citizen",
+		"rel_type":       "AS_PRESIDENT",
+		"transaction_id": "2162-01_tr_01",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	// Create a minister with an appointee under the outgoing president
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Fisheries",
+		"date":           "2025-04-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2162-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Fisheries",
+		"child":          "Douglas Devananda",
+		"date":           "2025-04-01",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_APPOINTED",
+		"transaction_id": "2162-01_tr_03",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	// Move the minister to the new president and reassign its appointees
+	err = client.MoveMinister(map[string]interface{}{
+		"old_parent": "Ranil Wickremesinghe",
+		"new_parent": "Maithripala Sirisena",
+		"child":      "Minister of Fisheries",
+		"type":       "minister",
+		"date":       "2025-04-05",
+		"appointees": "reassign",
+	})
+	assert.NoError(t, err)
+
+	minister, err := client.GetActiveMinisterByPresident("Maithripala Sirisena", "Minister of Fisheries", "2025-04-05T00:00:00Z")
+	assert.NoError(t, err)
+
+	peopleRelations, err := client.GetRelatedEntities(minister.ID, &models.Relationship{Name: "AS_APPOINTED"})
+	assert.NoError(t, err)
+
+	newPresident, err := client.GetPresidentByGovernment("Maithripala Sirisena")
+	assert.NoError(t, err)
+
+	// The previous appointee is terminated and the new president is the only active appointee
+	var activePeopleRelations []models.Relationship
+	for _, rel := range peopleRelations {
+		if rel.EndTime == "" {
+			activePeopleRelations = append(activePeopleRelations, rel)
+		} else {
+			assert.Equal(t, "2025-04-05T00:00:00Z", rel.EndTime)
+		}
+	}
+	assert.Len(t, activePeopleRelations, 1)
+	assert.Equal(t, newPresident.ID, activePeopleRelations[0].RelatedEntityID)
+}
+
+func TestMoveMinisterInvalidAppointeesOption(t *testing.T) {
+	err := client.MoveMinister(map[string]interface{}{
+		"old_parent": "Ranil Wickremesinghe",
+		"new_parent": "Ranil Wickremesinghe",
+		"child":      "Minister of Defence",
+		"type":       "minister",
+		"date":       "2025-04-05",
+		"appointees": "promote",
+	})
+	assert.Error(t, err)
+}
+
+func TestTransitionPresidency(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	entityCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	// Create a president with a minister that will be handed over
+	_, err := client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Government of Sri Lanka",
+		"child":          "Mahinda Rajapaksa",
+		"date":           "2025-05-01",
+		"parent_type":    "government",
+		"child_type":     "citizen",
+		"rel_type":       "AS_PRESIDENT",
+		"transaction_id": "2163-01_tr_01",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Mahinda Rajapaksa",
+		"child":          "Minister of Highways",
+		"date":           "2025-05-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2163-01_tr_02",
+		"president":      "Mahinda Rajapaksa",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	// Hand over to a new president
+	summary, err := client.TransitionPresidency(map[string]interface{}{
+		"old_president":  "Mahinda Rajapaksa",
+		"new_president":  "Chandrika Kumaratunga",
+		"date":           "2025-05-10",
+		"ministers":      "transfer",
+		"appointees":     "reassign",
+		"transaction_id": "2163-02_tr_01",
+	}, entityCounters)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Minister of Highways"}, summary.MinistersTransferred)
+	assert.Empty(t, summary.MinistersTerminated)
+
+	// The minister is now active under the incoming president
+	_, err = client.GetActiveMinisterByPresident("Chandrika Kumaratunga", "Minister of Highways", "2025-05-10T00:00:00Z")
+	assert.NoError(t, err)
+
+	// The outgoing president's term has ended and the incoming one has started
+	governmentResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, governmentResults)
+
+	oldPresident, err := client.GetPresidentByGovernment("Mahinda Rajapaksa")
+	assert.NoError(t, err)
+	oldRelations, err := client.GetRelatedEntities(governmentResults[0].ID, &models.Relationship{
+		Name:            "AS_PRESIDENT",
+		RelatedEntityID: oldPresident.ID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, oldRelations, 1)
+	assert.Equal(t, "2025-05-10T00:00:00Z", oldRelations[0].EndTime)
+
+	newPresident, err := client.GetPresidentByGovernment("Chandrika Kumaratunga")
+	assert.NoError(t, err)
+	newRelations, err := client.GetRelatedEntities(governmentResults[0].ID, &models.Relationship{
+		Name:            "AS_PRESIDENT",
+		RelatedEntityID: newPresident.ID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, newRelations, 1)
+	assert.Equal(t, "", newRelations[0].EndTime)
+	assert.Equal(t, "2025-05-10T00:00:00Z", newRelations[0].StartTime)
+}
+
+func TestReinstateAndAmendDepartment(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	departmentCounters := map[string]int{
+		"department": 0,
+	}
+
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Mass Media",
+		"date":           "2025-06-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2164-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Mass Media",
+		"child":          "Department of Information",
+		"date":           "2025-06-01",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2164-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, departmentCounters)
+	assert.NoError(t, err)
+
+	// Terminate the department by mistake
+	err = client.TerminateOrgEntity(map[string]interface{}{
+		"parent":      "Minister of Mass Media",
+		"child":       "Department of Information",
+		"date":        "2025-06-10",
+		"parent_type": "minister",
+		"child_type":  "department",
+		"rel_type":    "AS_DEPARTMENT",
+		"president":   "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	// A reason is required
+	reinstateTransaction := map[string]interface{}{
+		"parent":         "Minister of Mass Media",
+		"child":          "Department of Information",
+		"date":           "2025-06-12",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"end_date":       "2025-06-10",
+		"transaction_id": "2164-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}
+	err = client.ReinstateRelationship(reinstateTransaction)
+	assert.Error(t, err)
+
+	// Reopen the relationship
+	reinstateTransaction["reason"] = "Department was terminated by mistake"
+	err = client.ReinstateRelationship(reinstateTransaction)
+	assert.NoError(t, err)
+
+	minister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Mass Media", "2025-06-12T00:00:00Z")
+	assert.NoError(t, err)
+
+	departmentResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "department",
+		},
+		Name: "Department of Information",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, departmentResults, 1)
+
+	// Read the relationship back: it is open again from its original start
+	relations, err := client.GetRelatedEntities(minister.ID, &models.Relationship{
+		RelatedEntityID: departmentResults[0].ID,
+		Name:            "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+
+	var activeRelations []models.Relationship
+	for _, rel := range relations {
+		if rel.EndTime == "" {
+			activeRelations = append(activeRelations, rel)
+		} else {
+			assert.Equal(t, rel.StartTime, rel.EndTime, "The ended relationship should be replaced")
+		}
+	}
+	assert.Len(t, activeRelations, 1)
+	assert.Equal(t, "", activeRelations[0].EndTime)
+	assert.Equal(t, "2025-06-01T00:00:00Z", activeRelations[0].StartTime)
+
+	// The department is held on the dates it was wrongly terminated
+	activeAt, err := client.GetRelatedEntities(minister.ID, &models.Relationship{
+		RelatedEntityID: departmentResults[0].ID,
+		Name:            "AS_DEPARTMENT",
+		ActiveAt:        "2025-06-11T00:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, activeAt, 1)
+
+	// Correct the start date of the relationship
+	err = client.AmendRelationship(map[string]interface{}{
+		"parent":         "Minister of Mass Media",
+		"child":          "Department of Information",
+		"date":           "2025-06-12",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"new_start":      "2025-06-02",
+		"reason":         "Gazette came into effect a day later",
+		"transaction_id": "2164-02_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	relations, err = client.GetRelatedEntities(minister.ID, &models.Relationship{
+		RelatedEntityID: departmentResults[0].ID,
+		Name:            "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+	activeRelations = nil
+	for _, rel := range relations {
+		if rel.EndTime == "" {
+			activeRelations = append(activeRelations, rel)
+		}
+	}
+	assert.Len(t, activeRelations, 1)
+	assert.Equal(t, "2025-06-02T00:00:00Z", activeRelations[0].StartTime)
+}
+
+func TestMinisterClassification(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+
+	// Category given explicitly, inferred from the name, and a project minister
+	ministers := []struct {
+		name     string
+		category string
+		position string
+	}{
+		{"Minister of Youth Affairs", "", "2"},
+		{"State Minister of Rural Roads", "", "5"},
+		{"Minister of Digital Infrastructure", "project", ""},
+	}
+	for i, m := range ministers {
+		_, err := client.AddOrgEntity(map[string]interface{}{
+			"parent":           "Ranil Wickremesinghe",
+			"child":            m.name,
+			"date":             "2025-04-01",
+			"parent_type":      "citizen",
+			"child_type":       "minister",
+			"rel_type":         "AS_MINISTER",
+			"transaction_id":   fmt.Sprintf("2173-01_tr_%02d", i+1),
+			"president":        "Ranil Wickremesinghe",
+			"category":         m.category,
+			"gazette_position": m.position,
+		}, ministerCounters)
+		assert.NoError(t, err)
+	}
+
+	// An unknown category is rejected
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Nothing",
+		"date":           "2025-04-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2173-01_tr_04",
+		"president":      "Ranil Wickremesinghe",
+		"category":       "honorary",
+	}, ministerCounters)
+	assert.Error(t, err)
+
+	// Renaming keeps the category and gazette position
+	_, err = client.RenameMinister(map[string]interface{}{
+		"old":            "Minister of Youth Affairs",
+		"new":            "Minister of Youth Affairs and Sports",
+		"type":           "minister",
+		"date":           "2025-04-10",
+		"transaction_id": "2173-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	renamed, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Youth Affairs and Sports", "2025-04-10T00:00:00Z")
+	assert.NoError(t, err)
+	classification, err := client.GetMinisterClassification(renamed.ID, "Minister of Youth Affairs and Sports", "2025-04-10T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, api.MinisterCategoryCabinet, classification.Category)
+	assert.Equal(t, 2, classification.GazettePosition)
+
+	// Filter the ministers by category
+	stateMinisters, err := client.GetMinistersByCategory("Ranil Wickremesinghe", api.MinisterCategoryState, "2025-04-15T00:00:00Z")
+	assert.NoError(t, err)
+	var stateNames []string
+	for _, m := range stateMinisters {
+		stateNames = append(stateNames, m.Name)
+	}
+	assert.Contains(t, stateNames, "State Minister of Rural Roads")
+	assert.NotContains(t, stateNames, "Minister of Youth Affairs and Sports")
+
+	projectMinisters, err := client.GetMinistersByCategory("Ranil Wickremesinghe", api.MinisterCategoryProject, "2025-04-15T00:00:00Z")
+	assert.NoError(t, err)
+	var projectNames []string
+	for _, m := range projectMinisters {
+		projectNames = append(projectNames, m.Name)
+	}
+	assert.Contains(t, projectNames, "Minister of Digital Infrastructure")
+}
+
+func TestInstitutionTypesAndUnits(t *testing.T) {
+	entityCounters := map[string]int{
+		"minister": 0,
+	}
+	for _, institutionType := range api.InstitutionTypes {
+		entityCounters[institutionType] = 0
+	}
+
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Power and Energy",
+		"date":           "2025-05-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2174-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	// Institutions of different types under the minister and a unit nested below one of them
+	institutions := []struct {
+		parent     string
+		parentType string
+		child      string
+		childType  string
+		relType    string
+	}{
+		{"Minister of Power and Energy", "minister", "Department of Energy Planning", "department", "AS_DEPARTMENT"},
+		{"Minister of Power and Energy", "minister", "Power Sector Regulatory Board", "statutory_board", "AS_DEPARTMENT"},
+		{"Minister of Power and Energy", "minister", "Energy Distribution Corporation", "public_corporation", "AS_DEPARTMENT"},
+		{"Energy Distribution Corporation", "public_corporation", "Grid Maintenance Company Ltd.", "state_owned_company", "AS_UNIT"},
+	}
+	for i, inst := range institutions {
+		entityCounters[inst.childType], err = client.AddOrgEntity(map[string]interface{}{
+			"parent":         inst.parent,
+			"child":          inst.child,
+			"date":           "2025-05-01",
+			"parent_type":    inst.parentType,
+			"child_type":     inst.childType,
+			"rel_type":       inst.relType,
+			"transaction_id": fmt.Sprintf("2174-01_tr_%02d", i+2),
+			"president":      "Ranil Wickremesinghe",
+		}, entityCounters)
+		assert.NoError(t, err)
+	}
+
+	// A nested unit must use AS_UNIT
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Energy Distribution Corporation",
+		"child":          "Metering Services Ltd.",
+		"date":           "2025-05-01",
+		"parent_type":    "public_corporation",
+		"child_type":     "state_owned_company",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2174-01_tr_06",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.Error(t, err)
+
+	corporationResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "public_corporation",
+		},
+		Name: "Energy Distribution Corporation",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, corporationResults, 1)
+	corporationID := corporationResults[0].ID
+
+	unitResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "state_owned_company",
+		},
+		Name: "Grid Maintenance Company Ltd.",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, unitResults, 1)
+	unitID := unitResults[0].ID
+
+	unitRelations, err := client.GetRelatedEntities(corporationID, &models.Relationship{
+		Name:            "AS_UNIT",
+		RelatedEntityID: unitID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, unitRelations, 1, "Should find AS_UNIT relationship from the corporation to its unit")
+
+	// Renaming the corporation keeps its unit
+	_, err = client.RenameDepartment(map[string]interface{}{
+		"old":            "Energy Distribution Corporation",
+		"new":            "Electricity Distribution Corporation",
+		"type":           "public_corporation",
+		"date":           "2025-05-10",
+		"transaction_id": "2174-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	renamedResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "public_corporation",
+		},
+		Name: "Electricity Distribution Corporation",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, renamedResults, 1)
+
+	renamedUnitRelations, err := client.GetRelatedEntities(renamedResults[0].ID, &models.Relationship{
+		Name:            "AS_UNIT",
+		RelatedEntityID: unitID,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, renamedUnitRelations, 1, "The unit should follow the renamed corporation")
+	assert.Equal(t, "", renamedUnitRelations[0].EndTime)
+
+	// Move the unit out to be held directly by the minister
+	err = client.MoveDepartment(map[string]interface{}{
+		"new_parent":         "Minister of Power and Energy",
+		"child":              "Grid Maintenance Company Ltd.",
+		"type":               "state_owned_company",
+		"date":               "2025-05-20",
+		"new_president_name": "Ranil Wickremesinghe",
+		"transaction_id":     "2174-03_tr_01",
+	})
+	assert.NoError(t, err)
+
+	// Terminate the statutory board
+	err = client.TerminateOrgEntity(map[string]interface{}{
+		"parent":      "Minister of Power and Energy",
+		"child":       "Power Sector Regulatory Board",
+		"date":        "2025-06-01",
+		"parent_type": "minister",
+		"child_type":  "statutory_board",
+		"rel_type":    "AS_DEPARTMENT",
+		"president":   "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	minister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Power and Energy", "2025-06-01T00:00:00Z")
+	assert.NoError(t, err)
+	departmentRelations, err := client.GetRelatedEntities(minister.ID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+
+	activeNames := map[string]bool{}
+	for _, rel := range departmentRelations {
+		if rel.EndTime != "" {
+			continue
+		}
+		results, err := client.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+		assert.NoError(t, err)
+		if len(results) > 0 {
+			activeNames[results[0].Name] = true
+		}
+	}
+	assert.True(t, activeNames["Grid Maintenance Company Ltd."], "The moved unit should be held by the minister")
+	assert.False(t, activeNames["Power Sector Regulatory Board"], "The statutory board should be terminated")
+}
+
+func TestDepartmentCustody(t *testing.T) {
+	entityCounters := map[string]int{"minister": 0, "department": 0}
+	for i, minister := range []string{"Minister of Custody Affairs", "Minister of Custody Transfers"} {
+		_, err := client.AddOrgEntity(map[string]interface{}{
+			"parent":         "Ranil Wickremesinghe",
+			"child":          minister,
+			"date":           "2025-12-01",
+			"parent_type":    "citizen",
+			"child_type":     "minister",
+			"rel_type":       "AS_MINISTER",
+			"transaction_id": fmt.Sprintf("2184-01_tr_0%d", i+1),
+			"president":      "Ranil Wickremesinghe",
+		}, entityCounters)
+		assert.NoError(t, err)
+	}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Custody Affairs",
+		"child":          "Department of Custody Records",
+		"date":           "2025-12-01",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2184-01_tr_03",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Department of Custody Records",
+		"child":          "Custody Archives Unit",
+		"date":           "2025-12-01",
+		"parent_type":    "department",
+		"child_type":     "state_owned_company",
+		"rel_type":       "AS_UNIT",
+		"transaction_id": "2184-01_tr_04",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"state_owned_company": 0})
+	assert.NoError(t, err)
+
+	// A rename keeps the department in the same hands, a move hands it over
+	_, err = client.RenameMinister(map[string]interface{}{
+		"old":            "Minister of Custody Affairs",
+		"new":            "Minister of Custody and Care",
+		"type":           "minister",
+		"date":           "2025-12-05",
+		"transaction_id": "2184-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	err = client.MoveDepartment(map[string]interface{}{
+		"old_parent":         "Minister of Custody and Care",
+		"new_parent":         "Minister of Custody Transfers",
+		"child":              "Department of Custody Records",
+		"type":               "department",
+		"date":               "2025-12-10",
+		"old_president_name": "Ranil Wickremesinghe",
+		"new_president_name": "Ranil Wickremesinghe",
+		"transaction_id":     "2184-03_tr_01",
+	})
+	assert.NoError(t, err)
+
+	histories, err := client.GetDepartmentCustodyByName("Department of Custody Records")
+	assert.NoError(t, err)
+	if assert.Len(t, histories, 1) {
+		history := histories[0]
+		assert.Equal(t, 1, history.Handovers)
+		if assert.Len(t, history.Custodians, 2) {
+			assert.Equal(t, []string{"Minister of Custody Affairs", "Minister of Custody and Care"}, history.Custodians[0].Ministers)
+			assert.Equal(t, "2025-12-01T00:00:00Z", history.Custodians[0].StartTime)
+			assert.Equal(t, "2025-12-10T00:00:00Z", history.Custodians[0].EndTime)
+			assert.Equal(t, []string{"Ranil Wickremesinghe"}, history.Custodians[0].Presidents)
+			assert.Equal(t, []string{"Minister of Custody Transfers"}, history.Custodians[1].Ministers)
+			assert.Equal(t, "", history.Custodians[1].EndTime)
+		}
+	}
+
+	// The report lists departments that changed hands, most handovers first
+	report, err := client.GetMostHandedOver(0)
+	assert.NoError(t, err)
+	found := false
+	for i, history := range report {
+		if i > 0 {
+			assert.LessOrEqual(t, history.Handovers, report[i-1].Handovers)
+		}
+		if history.Name == "Department of Custody Records" {
+			found = true
+		}
+	}
+	assert.True(t, found, "The moved department should be in the handover report")
+
+	// A nested unit stays with the institution holding it, under the presidents of its ministers
+	histories, err = client.GetDepartmentCustodyByName("Custody Archives Unit")
+	assert.NoError(t, err)
+	if assert.Len(t, histories, 1) {
+		assert.Equal(t, 0, histories[0].Handovers)
+		if assert.Len(t, histories[0].Custodians, 1) {
+			assert.Equal(t, []string{"Department of Custody Records"}, histories[0].Custodians[0].Ministers)
+			assert.Equal(t, []string{"Ranil Wickremesinghe"}, histories[0].Custodians[0].Presidents)
+		}
+	}
+
+	_, err = client.GetDepartmentCustodyByName("Department of Nobody's Custody")
+	assert.True(t, errors.Is(err, api.ErrNotFound))
+}
+
+func TestReinstatedRelationshipReaders(t *testing.T) {
+	entityCounters := map[string]int{"minister": 0, "department": 0, "citizen": 0}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Reinstated Affairs",
+		"date":           "2026-01-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2188-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Reinstated Affairs",
+		"child":          "Department of Reinstated Records",
+		"date":           "2026-01-01",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2188-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Reinstated Affairs",
+		"child":          "Reinstated Tester",
+		"date":           "2026-01-01",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_APPOINTED",
+		"transaction_id": "2188-01_tr_03",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	graph, err := client.GetGraph()
+	assert.NoError(t, err)
+	movedBefore := 0
+	for _, row := range api.ComputeStats(graph) {
+		movedBefore += row.PeopleMoved + row.DepartmentsMoved
+	}
+
+	// Terminate the department and the appointment by mistake, then reinstate both
+	err = client.TerminateOrgEntity(map[string]interface{}{
+		"parent":      "Minister of Reinstated Affairs",
+		"child":       "Department of Reinstated Records",
+		"date":        "2026-01-10",
+		"parent_type": "minister",
+		"child_type":  "department",
+		"rel_type":    "AS_DEPARTMENT",
+		"president":   "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+	err = client.TerminatePersonEntity(map[string]interface{}{
+		"parent":      "Minister of Reinstated Affairs",
+		"child":       "Reinstated Tester",
+		"date":        "2026-01-10",
+		"parent_type": "minister",
+		"child_type":  "citizen",
+		"rel_type":    "AS_APPOINTED",
+		"president":   "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	for i, reinstated := range []struct {
+		child     string
+		childType string
+		relType   string
+	}{
+		{"Department of Reinstated Records", "department", "AS_DEPARTMENT"},
+		{"Reinstated Tester", "citizen", "AS_APPOINTED"},
+	} {
+		err = client.ReinstateRelationship(map[string]interface{}{
+			"parent":         "Minister of Reinstated Affairs",
+			"child":          reinstated.child,
+			"date":           "2026-01-12",
+			"parent_type":    "minister",
+			"child_type":     reinstated.childType,
+			"rel_type":       reinstated.relType,
+			"reason":         "Terminated by mistake",
+			"transaction_id": fmt.Sprintf("2188-02_tr_0%d", i+1),
+			"president":      "Ranil Wickremesinghe",
+		})
+		assert.NoError(t, err)
+	}
+
+	// The replaced relationships are not seen by any reader
+	timeline, err := client.GetPersonTimelineByName("Reinstated Tester")
+	assert.NoError(t, err)
+	if assert.Len(t, timeline.Appointments, 1) {
+		assert.Equal(t, "2026-01-01T00:00:00Z", timeline.Appointments[0].StartTime)
+		assert.Equal(t, "", timeline.Appointments[0].EndTime)
+		assert.Equal(t, "", timeline.Appointments[0].EndReason)
+	}
+
+	histories, err := client.GetDepartmentCustodyByName("Department of Reinstated Records")
+	assert.NoError(t, err)
+	if assert.Len(t, histories, 1) {
+		assert.Equal(t, 0, histories[0].Handovers)
+		if assert.Len(t, histories[0].Custodians, 1) {
+			assert.Equal(t, "2026-01-01T00:00:00Z", histories[0].Custodians[0].StartTime)
+			assert.Equal(t, "", histories[0].Custodians[0].EndTime)
+		}
+	}
+
+	graph, err = client.GetGraph()
+	assert.NoError(t, err)
+	for _, rel := range graph.Relationships {
+		assert.False(t, rel.EndTime != "" && rel.EndTime == rel.StartTime, "Replaced relationship %s should not be exported", rel.ID)
+	}
+	movedAfter := 0
+	for _, row := range api.ComputeStats(graph) {
+		movedAfter += row.PeopleMoved + row.DepartmentsMoved
+	}
+	assert.Equal(t, movedBefore, movedAfter, "A reinstatement is not a move")
+}
+
+func TestRenameMinisterWithStatutoryBoard(t *testing.T) {
+	entityCounters := map[string]int{
+		"minister":        0,
+		"statutory_board": 0,
+	}
+
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Ports and Shipping",
+		"date":           "2025-07-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2187-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	entityCounters["statutory_board"], err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Ports and Shipping",
+		"child":          "Harbour Pilotage Board",
+		"date":           "2025-07-01",
+		"parent_type":    "minister",
+		"child_type":     "statutory_board",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2187-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	// Renaming the minister carries the statutory board over
+	_, err = client.RenameMinister(map[string]interface{}{
+		"old":            "Minister of Ports and Shipping",
+		"new":            "Minister of Ports, Shipping and Aviation",
+		"type":           "minister",
+		"date":           "2025-08-01",
+		"transaction_id": "2187-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, entityCounters)
+	assert.NoError(t, err)
+
+	newMinister, err := client.GetActiveMinisterByPresident("Ranil Wickremesinghe", "Minister of Ports, Shipping and Aviation", "2025-08-01T00:00:00Z")
+	assert.NoError(t, err)
+
+	relations, err := client.GetRelatedEntities(newMinister.ID, &models.Relationship{
+		Name: "AS_DEPARTMENT",
+	})
+	assert.NoError(t, err)
+
+	var activeNames []string
+	for _, rel := range relations {
+		if rel.EndTime != "" {
+			continue
+		}
+		results, err := client.SearchEntities(&models.SearchCriteria{ID: rel.RelatedEntityID})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "statutory_board", results[0].Kind.Minor)
+		activeNames = append(activeNames, results[0].Name)
+	}
+	assert.Equal(t, []string{"Harbour Pilotage Board"}, activeNames)
+}
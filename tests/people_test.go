@@ -1,6 +1,9 @@
 package tests
 
 import (
+	"fmt"
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
 	"orgchart_nexoan/models"
 	"testing"
 
@@ -1359,3 +1362,541 @@ func TestSwapMultiplePeople(t *testing.T) {
 		}
 	}
 }
+
+func TestAppointmentRoles(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	departmentCounters := map[string]int{
+		"department": 0,
+	}
+	personCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	// Create a minister with a department to appoint people to
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Tourism and Lands",
+		"date":           "2025-03-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2170-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Tourism and Lands",
+		"child":          "Department of Land Settlement",
+		"date":           "2025-03-01",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2170-01_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, departmentCounters)
+	assert.NoError(t, err)
+
+	// Appoint people with different roles
+	appointments := []struct {
+		transactionID string
+		parent        string
+		parentType    string
+		child         string
+		role          string
+		relType       string
+	}{
+		{"2170-02_tr_01", "Minister of Tourism and Lands", "minister", "Chandana Perera", "state_minister", "AS_STATE_MINISTER"},
+		{"2170-02_tr_02", "Minister of Tourism and Lands", "minister", "Kasun Silva", "deputy_minister", "AS_DEPUTY_MINISTER"},
+		{"2170-02_tr_03", "Minister of Tourism and Lands", "minister", "Nimali Fernando", "secretary", "AS_SECRETARY"},
+		{"2170-02_tr_04", "Department of Land Settlement", "department", "Ruwan Bandara", "department_head", "AS_HEAD"},
+	}
+
+	for _, tc := range appointments {
+		personCounters["citizen"], err = client.AddPersonEntity(map[string]interface{}{
+			"parent":         tc.parent,
+			"child":          tc.child,
+			"date":           "2025-03-02",
+			"parent_type":    tc.parentType,
+			"child_type":     "citizen",
+			"role":           tc.role,
+			"transaction_id": tc.transactionID,
+			"president":      "Ranil Wickremesinghe",
+		}, personCounters)
+		assert.NoError(t, err)
+
+		parentResults, err := client.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Organisation",
+				Minor: tc.parentType,
+			},
+			Name: tc.parent,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, parentResults, 1)
+
+		personResults, err := client.SearchEntities(&models.SearchCriteria{
+			Kind: &models.Kind{
+				Major: "Person",
+				Minor: "citizen",
+			},
+			Name: tc.child,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, personResults, 1)
+
+		relations, err := client.GetRelatedEntities(parentResults[0].ID, &models.Relationship{
+			RelatedEntityID: personResults[0].ID,
+			Name:            tc.relType,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, relations, 1, "Should find %s relationship for %s", tc.relType, tc.child)
+	}
+
+	// A role that cannot be held on a department is rejected
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Department of Land Settlement",
+		"child":          "Kasun Silva",
+		"date":           "2025-03-02",
+		"parent_type":    "department",
+		"child_type":     "citizen",
+		"role":           "state_minister",
+		"transaction_id": "2170-02_tr_05",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.Error(t, err)
+
+	// Unknown roles are rejected
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Tourism and Lands",
+		"child":          "Kasun Silva",
+		"date":           "2025-03-02",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"role":           "advisor",
+		"transaction_id": "2170-02_tr_06",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.Error(t, err)
+
+	// Terminating the minister ends every role held on it
+	err = client.TerminateOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Tourism and Lands",
+		"date":           "2025-04-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2170-03_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	ministerResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "minister",
+		},
+		Name: "Minister of Tourism and Lands",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ministerResults, 1)
+
+	for _, relType := range []string{"AS_STATE_MINISTER", "AS_DEPUTY_MINISTER", "AS_SECRETARY"} {
+		relations, err := client.GetRelatedEntities(ministerResults[0].ID, &models.Relationship{
+			Name: relType,
+		})
+		assert.NoError(t, err)
+		for _, rel := range relations {
+			assert.NotEmpty(t, rel.EndTime, "%s relationship should be terminated", relType)
+		}
+	}
+}
+
+func TestPrimeMinisterAppointment(t *testing.T) {
+	personCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	// Prime ministers are appointed to the government by rel_type, as in the people data
+	var err error
+	personCounters["citizen"], err = client.AddPersonEntity(map[string]interface{}{
+		"transaction_id": "2186-01_tr_01",
+		"parent":         "Government of Sri Lanka",
+		"parent_type":    "government",
+		"child":          "Harini Amarasuriya",
+		"child_type":     "citizen",
+		"rel_type":       "AS_PRIME_MINISTER",
+		"date":           "2024-09-25",
+		"president":      "Anura Kumara Dissanayake",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	governmentResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Organisation",
+			Minor: "government",
+		},
+		Name: "Government of Sri Lanka",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, governmentResults, 1)
+
+	personResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Person",
+			Minor: "citizen",
+		},
+		Name: "Harini Amarasuriya",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, personResults, 1)
+
+	relations, err := client.GetRelatedEntities(governmentResults[0].ID, &models.Relationship{
+		RelatedEntityID: personResults[0].ID,
+		Name:            "AS_PRIME_MINISTER",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, relations, 1)
+	assert.Empty(t, relations[0].EndTime)
+
+	// The appointment is ended the same way
+	err = client.TerminatePersonEntity(map[string]interface{}{
+		"transaction_id": "2186-02_tr_01",
+		"parent":         "Government of Sri Lanka",
+		"parent_type":    "government",
+		"child":          "Harini Amarasuriya",
+		"child_type":     "citizen",
+		"rel_type":       "AS_PRIME_MINISTER",
+		"date":           "2024-11-18",
+		"president":      "Anura Kumara Dissanayake",
+	})
+	assert.NoError(t, err)
+
+	relations, err = client.GetRelatedEntities(governmentResults[0].ID, &models.Relationship{
+		RelatedEntityID: personResults[0].ID,
+		Name:            "AS_PRIME_MINISTER",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, relations, 1)
+	assert.Equal(t, "2024-11-18T00:00:00Z", relations[0].EndTime)
+}
+
+func TestPersonIdentityResolution(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	personCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	registry, err := api.NewPersonRegistry([]models.PersonIdentity{
+		{PersonID: "person_herath_db", Name: "Don Bandara Herath", Aliases: []string{"D.B. Herath"}},
+		{PersonID: "person_perera_a_1", Name: "Ajith Perera", Aliases: []string{"A. Perera"}},
+		{PersonID: "person_perera_a_2", Name: "Anil Perera", Aliases: []string{"A. Perera"}},
+	})
+	assert.NoError(t, err)
+	client.SetPersonRegistry(registry)
+	defer client.SetPersonRegistry(nil)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Plantation Industries",
+		"date":           "2025-03-10",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2171-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	// Both spellings resolve to the same canonical person
+	for i, name := range []string{"D.B. Herath", "Don Bandara Herath"} {
+		role := "state_minister"
+		if i == 1 {
+			role = "deputy_minister"
+		}
+		personCounters["citizen"], err = client.AddPersonEntity(map[string]interface{}{
+			"parent":         "Minister of Plantation Industries",
+			"child":          name,
+			"date":           "2025-03-11",
+			"parent_type":    "minister",
+			"child_type":     "citizen",
+			"role":           role,
+			"transaction_id": "2171-02_tr_01",
+			"president":      "Ranil Wickremesinghe",
+		}, personCounters)
+		assert.NoError(t, err)
+	}
+
+	results, err := client.SearchEntities(&models.SearchCriteria{ID: "person_herath_db"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Don Bandara Herath", results[0].Name)
+
+	aliasResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Person",
+		},
+		Name: "D.B. Herath",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, aliasResults, 0, "The alias should not create a separate person")
+
+	// A name shared by two identities is ambiguous without a person_id
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Plantation Industries",
+		"child":          "A. Perera",
+		"date":           "2025-03-11",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"role":           "secretary",
+		"transaction_id": "2171-02_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+
+	// The person_id column picks the person explicitly
+	_, err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Plantation Industries",
+		"child":          "A. Perera",
+		"date":           "2025-03-11",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"role":           "secretary",
+		"person_id":      "person_perera_a_2",
+		"transaction_id": "2171-02_tr_02",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	results, err = client.SearchEntities(&models.SearchCriteria{ID: "person_perera_a_2"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Anil Perera", results[0].Name)
+}
+
+func TestPersonAttributes(t *testing.T) {
+	ministerCounters := map[string]int{
+		"minister": 0,
+	}
+	personCounters := map[string]int{
+		"citizen": 0,
+	}
+
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Fisheries",
+		"date":           "2025-03-15",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2172-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	// Attribute columns of the ADD file are written on the person
+	personCounters["citizen"], err = client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Fisheries",
+		"child":          "Saman Rathnayake",
+		"date":           "2025-03-16",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_APPOINTED",
+		"transaction_id": "2172-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+		"party":          "United National Party",
+		"electorate":     "Kandy",
+		"honorific":      "Hon.",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	// The party changes later through an ATTRIBUTE transaction
+	err = client.SetPersonAttribute(map[string]interface{}{
+		"transaction_id": "2172-03_tr_01",
+		"child":          "Saman Rathnayake",
+		"attribute":      "party",
+		"value":          "Samagi Jana Balawegaya",
+		"date":           "2025-06-01",
+	})
+	assert.NoError(t, err)
+
+	personResults, err := client.SearchEntities(&models.SearchCriteria{
+		Kind: &models.Kind{
+			Major: "Person",
+			Minor: "citizen",
+		},
+		Name: "Saman Rathnayake",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, personResults, 1)
+	personID := personResults[0].ID
+
+	party, err := client.GetAttributeAsOf(personID, "party", "2025-04-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, "United National Party", party)
+
+	party, err = client.GetAttributeAsOf(personID, "party", "2025-07-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, "Samagi Jana Balawegaya", party)
+
+	party, err = client.GetAttributeAsOf(personID, "party", "2025-01-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, "", party, "No party before the person was added")
+
+	attributes, err := client.GetPersonAttributesAsOf(personID, "2025-07-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Equal(t, "Kandy", attributes["electorate"])
+	assert.Equal(t, "Hon.", attributes["honorific"])
+
+	// Unknown people are rejected
+	err = client.SetPersonAttribute(map[string]interface{}{
+		"transaction_id": "2172-03_tr_02",
+		"child":          "Nobody Known",
+		"attribute":      "party",
+		"value":          "Independent",
+		"date":           "2025-06-01",
+	})
+	assert.Error(t, err)
+}
+
+func TestPresidentAliases(t *testing.T) {
+	aliases, err := api.NewPresidentAliases(map[string]string{
+		":RANIL": "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	// Built-in short codes, directory names and configured aliases resolve to the canonical name
+	for _, alias := range []string{":RW", "rw", ":RANIL", "Ranil Wickremesinghe"} {
+		name, err := aliases.Resolve(alias)
+		assert.NoError(t, err)
+		assert.Equal(t, "Ranil Wickremesinghe", name)
+	}
+	name, err := aliases.Resolve(":AK")
+	assert.NoError(t, err)
+	assert.Equal(t, "Anura Kumara Dissanayake", name)
+
+	// Plain names that are not aliases are kept, unknown short codes are rejected
+	name, err = aliases.Resolve("Some Future President")
+	assert.NoError(t, err)
+	assert.Equal(t, "Some Future President", name)
+	_, err = aliases.Resolve(":XYZ")
+	assert.Error(t, err)
+
+	// An alias must name a president
+	_, err = api.NewPresidentAliases(map[string]string{":X": ":RW"})
+	assert.Error(t, err)
+
+	// Alias files
+	loaded, err := api.LoadPresidentAliases("../data/sample_data/president_aliases.csv")
+	assert.NoError(t, err)
+	name, err = loaded.Resolve("ranil")
+	assert.NoError(t, err)
+	assert.Equal(t, "Ranil Wickremesinghe", name)
+	assert.Contains(t, loaded.Names(), "Maithripala Sirisena")
+}
+
+func TestPersonTimeline(t *testing.T) {
+	ministerCounters := map[string]int{"minister": 0}
+	personCounters := map[string]int{"citizen": 0}
+	for i, minister := range []string{"Minister of Timeline Affairs", "Minister of Timeline Records"} {
+		_, err := client.AddOrgEntity(map[string]interface{}{
+			"parent":         "Ranil Wickremesinghe",
+			"child":          minister,
+			"date":           "2025-11-01",
+			"parent_type":    "citizen",
+			"child_type":     "minister",
+			"rel_type":       "AS_MINISTER",
+			"transaction_id": fmt.Sprintf("2183-01_tr_0%d", i+1),
+			"president":      "Ranil Wickremesinghe",
+		}, ministerCounters)
+		assert.NoError(t, err)
+	}
+
+	_, err := client.AddPersonEntity(map[string]interface{}{
+		"parent":         "Minister of Timeline Affairs",
+		"child":          "Timeline Tester",
+		"date":           "2025-11-01",
+		"parent_type":    "minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_APPOINTED",
+		"transaction_id": "2183-01_tr_03",
+		"president":      "Ranil Wickremesinghe",
+	}, personCounters)
+	assert.NoError(t, err)
+
+	err = client.MovePerson(map[string]interface{}{
+		"old_parent": "Minister of Timeline Affairs",
+		"new_parent": "Minister of Timeline Records",
+		"child":      "Timeline Tester",
+		"type":       "AS_APPOINTED",
+		"date":       "2025-11-05",
+		"president":  "Ranil Wickremesinghe",
+	})
+	assert.NoError(t, err)
+
+	_, err = client.RenameMinister(map[string]interface{}{
+		"old":            "Minister of Timeline Records",
+		"new":            "Minister of Timeline Archives",
+		"type":           "minister",
+		"date":           "2025-11-10",
+		"transaction_id": "2183-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	timeline, err := client.GetPersonTimelineByName("Timeline Tester")
+	assert.NoError(t, err)
+	assert.Empty(t, timeline.Terms)
+	if assert.Len(t, timeline.Appointments, 3) {
+		first := timeline.Appointments[0]
+		assert.Equal(t, "Minister of Timeline Affairs", first.ParentName)
+		assert.Equal(t, "cabinet_minister", first.Role)
+		assert.Equal(t, "Ranil Wickremesinghe", first.President)
+		assert.Equal(t, api.AppointmentMoved, first.EndReason)
+		assert.Equal(t, "Minister of Timeline Records", first.Successor)
+
+		second := timeline.Appointments[1]
+		assert.Equal(t, "2025-11-10T00:00:00Z", second.EndTime)
+		assert.Equal(t, api.AppointmentRenamed, second.EndReason)
+		assert.Equal(t, "Minister of Timeline Archives", second.Successor)
+
+		third := timeline.Appointments[2]
+		assert.Equal(t, "Minister of Timeline Archives", third.ParentName)
+		assert.Equal(t, "", third.EndTime)
+		assert.Equal(t, "", third.EndReason)
+	}
+
+	// Presidents have terms
+	president, err := client.GetPersonTimelineByName("Ranil Wickremesinghe")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, president.Terms)
+
+	_, err = client.GetPersonTimelineByName("Nobody With This Name")
+	assert.Error(t, err)
+}
+
+func TestDescribeAppointment(t *testing.T) {
+	appointment := models.CareerAppointment{
+		Role:       "state_minister",
+		ParentName: "Minister of A",
+		ParentType: "minister",
+		President:  "Ranil Wickremesinghe",
+		StartTime:  "2022-07-22T00:00:00Z",
+		EndTime:    "2023-01-10T00:00:00Z",
+		EndReason:  api.AppointmentRenamed,
+		Successor:  "Minister of B",
+	}
+	assert.Equal(t, "2022-07-22 to 2023-01-10 state minister of Minister of A (Ranil Wickremesinghe), carried over when renamed to Minister of B", export.DescribeAppointment(appointment))
+
+	appointment.EndReason = api.AppointmentParentTerminated
+	assert.Equal(t, "2022-07-22 to 2023-01-10 state minister of Minister of A (Ranil Wickremesinghe), ended with the minister", export.DescribeAppointment(appointment))
+
+	appointment.EndTime, appointment.EndReason, appointment.President = "", "", ""
+	assert.Equal(t, "since 2022-07-22 state minister of Minister of A", export.DescribeAppointment(appointment))
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"orgchart_nexoan/models"
+	"orgchart_nexoan/server"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Served Affairs",
+		"date":           "2025-09-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2185-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"minister": 0})
+	assert.NoError(t, err)
+
+	service := httptest.NewServer(server.New(client, time.Minute))
+	defer service.Close()
+
+	// The minister view lists the minister with its president
+	response, err := http.Get(service.URL + "/minister?date=2025-09-02&name=Minister+of+Served+Affairs")
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	var views []models.MinisterView
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&views))
+	if assert.Len(t, views, 1) {
+		assert.Equal(t, "Ranil Wickremesinghe", views[0].President)
+		assert.Equal(t, "Minister of Served Affairs", views[0].Minister.Name)
+	}
+
+	// Revalidating with the ETag answers 304 Not Modified
+	etag := response.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	request, err := http.NewRequest(http.MethodGet, service.URL+"/minister?name=Minister+of+Served+Affairs&date=2025-09-02", nil)
+	assert.NoError(t, err)
+	request.Header.Set("If-None-Match", etag)
+	revalidated, err := http.DefaultClient.Do(request)
+	assert.NoError(t, err)
+	revalidated.Body.Close()
+	assert.Equal(t, http.StatusNotModified, revalidated.StatusCode)
+
+	// Before it was added the minister is not found
+	missing, err := http.Get(service.URL + "/minister?date=2025-08-01&name=Minister+of+Served+Affairs")
+	assert.NoError(t, err)
+	missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+
+	// Unknown people and entities are not found
+	for _, path := range []string{"/person?name=Nobody+Known", "/lineage?name=Nothing+Known"} {
+		unknown, err := http.Get(service.URL + path)
+		assert.NoError(t, err)
+		unknown.Body.Close()
+		assert.Equal(t, http.StatusNotFound, unknown.StatusCode, path)
+	}
+
+	// Invalid parameters are rejected
+	for _, path := range []string{"/snapshot", "/snapshot?date=2025-13-01", "/diff?from=2025-09-02&to=2025-09-01", "/diff?from=2025-09-02&to=2025-09-02", "/person", "/lineage?id=a&name=b"} {
+		invalid, err := http.Get(service.URL + path)
+		assert.NoError(t, err)
+		invalid.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, invalid.StatusCode, path)
+	}
+
+	// Only GET is served
+	posted, err := http.Post(service.URL+"/snapshot?date=2025-09-02", "application/json", nil)
+	assert.NoError(t, err)
+	posted.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, posted.StatusCode)
+}
@@ -0,0 +1,299 @@
+package tests
+
+import (
+	"bytes"
+	"orgchart_nexoan/api"
+	"orgchart_nexoan/export"
+	"orgchart_nexoan/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// findSnapshotNode returns the first node below (or at) the given node with the given name
+func findSnapshotNode(node models.SnapshotNode, name string) *models.SnapshotNode {
+	if node.Name == name {
+		return &node
+	}
+	for _, child := range node.Children {
+		if found := findSnapshotNode(child, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func TestSnapshot(t *testing.T) {
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Snapshot Affairs",
+		"date":           "2025-08-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2180-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"minister": 0})
+	assert.NoError(t, err)
+
+	_, err = client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Minister of Snapshot Affairs",
+		"child":          "Department of Snapshots",
+		"date":           "2025-08-05",
+		"parent_type":    "minister",
+		"child_type":     "department",
+		"rel_type":       "AS_DEPARTMENT",
+		"transaction_id": "2180-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"department": 0})
+	assert.NoError(t, err)
+
+	// The minister is held by the president, the department only from its own start date
+	snapshot, err := client.GetSnapshot("2025-08-02T00:00:00Z")
+	assert.NoError(t, err)
+	president := findSnapshotNode(snapshot.Government, "Ranil Wickremesinghe")
+	if assert.NotNil(t, president) {
+		minister := findSnapshotNode(*president, "Minister of Snapshot Affairs")
+		if assert.NotNil(t, minister) {
+			assert.Equal(t, "AS_MINISTER", minister.Relationship)
+			assert.Equal(t, "2025-08-01T00:00:00Z", minister.StartTime)
+			assert.Nil(t, findSnapshotNode(*minister, "Department of Snapshots"))
+		}
+	}
+
+	snapshot, err = client.GetSnapshot("2025-08-10T00:00:00Z")
+	assert.NoError(t, err)
+	department := findSnapshotNode(snapshot.Government, "Department of Snapshots")
+	if assert.NotNil(t, department) {
+		assert.Equal(t, "AS_DEPARTMENT", department.Relationship)
+	}
+
+	// Nothing of the minister exists before it was created
+	snapshot, err = client.GetSnapshot("2025-07-01T00:00:00Z")
+	assert.NoError(t, err)
+	assert.Nil(t, findSnapshotNode(snapshot.Government, "Minister of Snapshot Affairs"))
+}
+
+func TestSnapshotPrimeMinister(t *testing.T) {
+	_, err := client.AddPersonEntity(map[string]interface{}{
+		"transaction_id": "2190-01_tr_01",
+		"parent":         "Government of Sri Lanka",
+		"parent_type":    "government",
+		"child":          "Snapshot Prime Minister",
+		"child_type":     "citizen",
+		"rel_type":       "AS_PRIME_MINISTER",
+		"date":           "2025-08-01",
+		"president":      "Ranil Wickremesinghe",
+	}, map[string]int{"citizen": 0})
+	assert.NoError(t, err)
+
+	// The prime minister is appointed to the government, next to the president
+	snapshot, err := client.GetSnapshot("2025-08-02T00:00:00Z")
+	assert.NoError(t, err)
+	var primeMinister *models.SnapshotNode
+	for i, child := range snapshot.Government.Children {
+		if child.Name == "Snapshot Prime Minister" {
+			primeMinister = &snapshot.Government.Children[i]
+		}
+	}
+	if assert.NotNil(t, primeMinister) {
+		assert.Equal(t, "AS_PRIME_MINISTER", primeMinister.Relationship)
+		assert.Equal(t, "prime_minister", primeMinister.Role)
+		assert.Empty(t, primeMinister.Children)
+	}
+
+	// The appointment shows up in the diff as well
+	diff, err := client.GetDiff("2025-07-01T00:00:00Z", "2025-08-02T00:00:00Z")
+	assert.NoError(t, err)
+	found := false
+	for _, change := range diff.Changes {
+		if change.Name == "Snapshot Prime Minister" {
+			found = true
+		}
+	}
+	assert.True(t, found, "The prime minister appointment should be in the diff")
+}
+
+func TestWriteSnapshotCSV(t *testing.T) {
+	snapshot := &models.Snapshot{
+		Date: "2022-05-14T00:00:00Z",
+		Government: models.SnapshotNode{
+			ID:   "gov_01",
+			Name: "Government of Sri Lanka",
+			Type: "government",
+			Children: []models.SnapshotNode{
+				{
+					ID:           "2152-12_cit_1",
+					Name:         "Ranil Wickremesinghe",
+					Type:         "citizen",
+					Relationship: "AS_PRESIDENT",
+					StartTime:    "2019-12-01T00:00:00Z",
+					Sources:      []string{"2152-12"},
+					Children: []models.SnapshotNode{
+						{
+							ID:           "2153-12_min_1",
+							Name:         "Minister of Defence",
+							Type:         "minister",
+							Relationship: "AS_MINISTER",
+							StartTime:    "2019-12-10T00:00:00Z",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := export.WriteSnapshotCSV(&buf, snapshot)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "date,level,parent_id,parent_name,id,name,type,relationship,role,start_date,sources", lines[0])
+	assert.Equal(t, "2022-05-14,1,gov_01,Government of Sri Lanka,2152-12_cit_1,Ranil Wickremesinghe,citizen,AS_PRESIDENT,,2019-12-01,2152-12", lines[2])
+	assert.Equal(t, "2022-05-14,2,2152-12_cit_1,Ranil Wickremesinghe,2153-12_min_1,Minister of Defence,minister,AS_MINISTER,,2019-12-10,", lines[3])
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	person := func(id, name, role, start string) models.SnapshotNode {
+		return models.SnapshotNode{ID: id, Name: name, Type: "citizen", Relationship: "AS_APPOINTED", Role: role, StartTime: start}
+	}
+	department := func(id, name, start string) models.SnapshotNode {
+		return models.SnapshotNode{ID: id, Name: name, Type: "department", Relationship: "AS_DEPARTMENT", StartTime: start}
+	}
+	snapshot := func(date string, ministers ...models.SnapshotNode) *models.Snapshot {
+		return &models.Snapshot{
+			Date: date,
+			Government: models.SnapshotNode{
+				ID: "gov_01", Name: "Government of Sri Lanka", Type: "government",
+				Children: []models.SnapshotNode{
+					{ID: "rw", Name: "Ranil Wickremesinghe", Type: "citizen", Relationship: "AS_PRESIDENT", Children: ministers},
+				},
+			},
+		}
+	}
+
+	from := snapshot("2022-05-14T00:00:00Z",
+		models.SnapshotNode{ID: "min_1", Name: "Minister of Health", Type: "minister", Relationship: "AS_MINISTER",
+			Children: []models.SnapshotNode{person("p1", "Keheliya Rambukwella", "cabinet_minister", ""), department("dep_1", "Department of Ayurveda", "")}},
+		models.SnapshotNode{ID: "min_2", Name: "Minister of Finance", Type: "minister", Relationship: "AS_MINISTER",
+			Children: []models.SnapshotNode{person("p2", "Ali Sabry", "cabinet_minister", ""), department("dep_2", "General Treasury", "")}},
+		models.SnapshotNode{ID: "min_3", Name: "Minister of Sports", Type: "minister", Relationship: "AS_MINISTER"},
+	)
+	to := snapshot("2022-07-22T00:00:00Z",
+		// Health was renamed, its minister carried over
+		models.SnapshotNode{ID: "min_4", Name: "Minister of Health and Indigenous Medicine", Type: "minister", Relationship: "AS_MINISTER",
+			Children: []models.SnapshotNode{person("p1", "Keheliya Rambukwella", "cabinet_minister", "2022-07-22T00:00:00Z"), department("dep_1", "Department of Ayurveda", "2022-07-22T00:00:00Z")}},
+		// The treasury moved to Finance's new neighbour and Ali Sabry moved there too
+		models.SnapshotNode{ID: "min_2", Name: "Minister of Finance", Type: "minister", Relationship: "AS_MINISTER"},
+		models.SnapshotNode{ID: "min_5", Name: "Minister of Economic Stabilization", Type: "minister", Relationship: "AS_MINISTER", StartTime: "2022-07-20T00:00:00Z",
+			Children: []models.SnapshotNode{person("p2", "Ali Sabry", "cabinet_minister", "2022-07-20T00:00:00Z"), department("dep_2", "General Treasury", "2022-07-20T00:00:00Z")}},
+	)
+	successors := map[string][]models.Relationship{
+		"min_1": {{RelatedEntityID: "min_4", Name: "RENAMED_TO", StartTime: "2022-07-22T00:00:00Z"}},
+	}
+
+	diff := api.DiffSnapshots(from, to, successors)
+	var changes []string
+	for _, change := range diff.Changes {
+		changes = append(changes, export.DescribeChange(change))
+	}
+	assert.Equal(t, []string{
+		"+ minister Minister of Economic Stabilization added under Ranil Wickremesinghe (2022-07-20)",
+		"- minister Minister of Sports removed from Ranil Wickremesinghe",
+		"~ Minister of Health renamed to Minister of Health and Indigenous Medicine (2022-07-22)",
+		"> General Treasury moved from Minister of Finance to Minister of Economic Stabilization (2022-07-20)",
+		"> Ali Sabry moved as cabinet minister from Minister of Finance to Minister of Economic Stabilization (2022-07-20)",
+	}, changes)
+}
+
+func TestDiff(t *testing.T) {
+	ministerCounters := map[string]int{"minister": 0}
+	_, err := client.AddOrgEntity(map[string]interface{}{
+		"parent":         "Ranil Wickremesinghe",
+		"child":          "Minister of Diff Affairs",
+		"date":           "2025-09-01",
+		"parent_type":    "citizen",
+		"child_type":     "minister",
+		"rel_type":       "AS_MINISTER",
+		"transaction_id": "2181-01_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	_, err = client.RenameMinister(map[string]interface{}{
+		"old":            "Minister of Diff Affairs",
+		"new":            "Minister of Diff and Compare Affairs",
+		"type":           "minister",
+		"date":           "2025-09-10",
+		"transaction_id": "2181-02_tr_01",
+		"president":      "Ranil Wickremesinghe",
+	}, ministerCounters)
+	assert.NoError(t, err)
+
+	// The rename is reported once instead of a removal and an addition
+	diff, err := client.GetDiff("2025-09-05T00:00:00Z", "2025-09-15T00:00:00Z")
+	assert.NoError(t, err)
+	var renamed *models.OrgChartChange
+	for i, change := range diff.Changes {
+		assert.NotEqual(t, "Minister of Diff Affairs", change.Name)
+		if change.Name == "Minister of Diff and Compare Affairs" {
+			renamed = &diff.Changes[i]
+		}
+	}
+	if assert.NotNil(t, renamed) {
+		assert.Equal(t, api.ChangeRenamed, renamed.Action)
+		assert.Equal(t, []string{"Minister of Diff Affairs"}, renamed.From)
+	}
+
+	_, err = client.GetDiff("2025-09-15T00:00:00Z", "2025-09-05T00:00:00Z")
+	assert.Error(t, err)
+}
+
+func TestRenderChart(t *testing.T) {
+	snapshot := &models.Snapshot{
+		Date:  "2022-05-01T00:00:00Z",
+		Until: "2022-07-31T00:00:00Z",
+		Government: models.SnapshotNode{
+			ID: "gov_01", Name: "Government of Sri Lanka", Type: "government",
+			Children: []models.SnapshotNode{
+				{
+					ID: "rw", Name: "Ranil Wickremesinghe", Type: "citizen", Relationship: "AS_PRESIDENT", StartTime: "2022-07-21T00:00:00Z",
+					Children: []models.SnapshotNode{
+						{
+							ID: "min_1", Name: "Minister of Health", Type: "minister", Relationship: "AS_MINISTER", StartTime: "2022-07-22T00:00:00Z", Sources: []string{"2289-43"},
+							Children: []models.SnapshotNode{
+								{ID: "p1", Name: "Keheliya Rambukwella", Type: "citizen", Relationship: "AS_APPOINTED", Role: "cabinet_minister", StartTime: "2022-07-22T00:00:00Z"},
+								{ID: "dep_1", Name: "Department of Ayurveda", Type: "department", Relationship: "AS_DEPARTMENT", StartTime: "2022-07-22T00:00:00Z", EndTime: "2022-07-30T00:00:00Z"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var dot bytes.Buffer
+	err := export.WriteDOT(&dot, snapshot, snapshot.Government, export.RenderOptions{Sources: true})
+	assert.NoError(t, err)
+	assert.Contains(t, dot.String(), "subgraph \"cluster_min_1\" {")
+	assert.Contains(t, dot.String(), "\"min_1\" -> \"dep_1\" [label=\"AS_DEPARTMENT 2022-07-22 to 2022-07-30\", style=dashed, color=gray, fontcolor=gray];")
+	assert.Contains(t, dot.String(), "\"gazette_2289-43\" [label=\"Gazette 2289-43\", shape=note, fontsize=9];")
+	assert.NotContains(t, dot.String(), "Keheliya Rambukwella")
+
+	var mermaid bytes.Buffer
+	err = export.WriteMermaid(&mermaid, snapshot, snapshot.Government, export.RenderOptions{Appointees: true})
+	assert.NoError(t, err)
+	assert.Contains(t, mermaid.String(), "subgraph n_cluster_min_1[\"Minister of Health\"]")
+	assert.Contains(t, mermaid.String(), "n_min_1 -->|\"cabinet minister 2022-07-22\"| n_p1")
+	assert.Contains(t, mermaid.String(), "n_min_1 -.->|\"AS_DEPARTMENT 2022-07-22 to 2022-07-30\"| n_dep_1")
+	assert.Contains(t, mermaid.String(), "class n_dep_1 terminated")
+
+	// Subtrees are selected by type and name
+	minister, err := export.SelectSubtree(snapshot, "minister", "Minister of Health")
+	assert.NoError(t, err)
+	assert.Equal(t, "min_1", minister.ID)
+	_, err = export.SelectSubtree(snapshot, "minister", "Minister of Nothing")
+	assert.Error(t, err)
+}